@@ -9,7 +9,7 @@
   # request rejected
   [ "$status" -eq 0 ]
   [ $(expr "$output" : '.*allowed.*false') -ne 0 ]
-  [ $(expr "$output" : ".*pod label with key level not allowed, the word is a palindrome.*") -ne 0 ]
+  [ $(expr "$output" : ".*label with key level at metadata.labels.level not allowed, the word is a palindrome.*") -ne 0 ]
 }
 
 @test "accept because pod has not a palindrome key label" {
@@ -45,7 +45,7 @@ func TestPolicyApply(t *testing.T) {
 		Assess("should not create a pod with a palindrome label", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 			p := utils.RunCommand(fmt.Sprintf("kubectl apply -f %s", "fixtures/palindrome-pod-descriptor.yml"))
 			assert.Error(t, p.Err())
-			assert.Contains(t, p.Result(), "pod label with key level not allowed, the word is a palindrome")
+			assert.Contains(t, p.Result(), "label with key level at metadata.labels.level not allowed, the word is a palindrome")
 			return ctx
 		}).
 		Assess("should create a pod with a non palindrome label", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
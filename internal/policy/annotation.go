@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// wellKnownAnnotationPrefixes are the prefixes of the annotations added by
+// kubectl and by the controllers, ignored when requested by the settings.
+func wellKnownAnnotationPrefixes() []string {
+	return []string{"kubectl.kubernetes.io/", "deployment.kubernetes.io/"}
+}
+
+// AnnotationSettings enables the validation of the annotation keys, they
+// are checked in the same label key scope of the labels.
+type AnnotationSettings struct {
+	// Palindrome annotation keys, or parts of them, accepted by the policy.
+	// The allowed palindromes of the labels do not apply to the annotations.
+	AllowedPalindromes []string `json:"allowed_palindromes,omitempty"`
+	// Annotation keys starting with one of the prefixes are not validated.
+	IgnoredPrefixes []string `json:"ignored_prefixes,omitempty"`
+	// Ignore the annotations added by kubectl and by the controllers.
+	IgnoreWellKnownPrefixes bool `json:"ignore_well_known_prefixes,omitempty"`
+}
+
+type InvalidAnnotationSettingsError struct {
+	Reason string
+}
+
+func (e InvalidAnnotationSettingsError) Error() string {
+	return fmt.Sprintf("annotations settings not valid: %s", e.Reason)
+}
+
+func (a *AnnotationSettings) Validate() error {
+	if _, err := CompilePatterns(a.AllowedPalindromes); err != nil {
+		return err
+	}
+	for _, prefix := range a.IgnoredPrefixes {
+		if prefix == "" {
+			return InvalidAnnotationSettingsError{Reason: "an ignored prefix cannot be empty"}
+		}
+	}
+	return nil
+}
+
+// isIgnored reports whether the annotation key starts with an ignored prefix.
+func (a *AnnotationSettings) isIgnored(annotationKey string) bool {
+	prefixes := a.IgnoredPrefixes
+	if a.IgnoreWellKnownPrefixes {
+		prefixes = append(wellKnownAnnotationPrefixes(), prefixes...)
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(annotationKey, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsForbiddenAnnotationKey reports whether the detectors of the annotations
+// refuse the annotation key, like ValidateLabels does. Annotation keys are
+// never forbidden when their check is disabled.
+func (s *Settings) IsForbiddenAnnotationKey(annotationKey string) bool {
+	if !s.isCheckedAnnotationKey(annotationKey) {
+		return false
+	}
+	return s.isForbidden(annotationKey, SourceAnnotation)
+}
+
+// isCheckedAnnotationKey reports whether the annotation key is validated.
+func (s *Settings) isCheckedAnnotationKey(annotationKey string) bool {
+	return s.Annotations != nil && !s.Annotations.isIgnored(annotationKey)
+}
+
+func (s *Settings) isAnAllowedAnnotationPalindrome(palindrome string) bool {
+	if s.allowedAnnotationPalindromePatterns == nil {
+		s.allowedAnnotationPalindromePatterns = compileValidPatterns(s.Annotations.AllowedPalindromes)
+	}
+	return s.allowedAnnotationPalindromePatterns.MatchAny(palindrome)
+}
@@ -0,0 +1,201 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsForbiddenAnnotationKey(t *testing.T) {
+	type testCase struct {
+		name          string
+		settings      policy.Settings
+		annotationKey string
+		forbidden     bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:          "annotation keys not checked by default",
+			settings:      policy.Settings{},
+			annotationKey: "level",
+			forbidden:     false,
+		},
+		{
+			name:          "palindrome annotation key",
+			settings:      policy.Settings{Annotations: &policy.AnnotationSettings{}},
+			annotationKey: "level",
+			forbidden:     true,
+		},
+		{
+			name:          "not palindrome annotation key",
+			settings:      policy.Settings{Annotations: &policy.AnnotationSettings{}},
+			annotationKey: "owner",
+			forbidden:     false,
+		},
+		{
+			name: "annotation key allowed by the annotations allowlist",
+			settings: policy.Settings{
+				Annotations: &policy.AnnotationSettings{AllowedPalindromes: []string{"level"}},
+			},
+			annotationKey: "level",
+			forbidden:     false,
+		},
+		{
+			name: "allowed palindromes of the labels do not apply to the annotations",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"level"},
+				Annotations:        &policy.AnnotationSettings{},
+			},
+			annotationKey: "level",
+			forbidden:     true,
+		},
+		{
+			name: "annotation key checked in the label key scope",
+			settings: policy.Settings{
+				LabelKeyScope: policy.LabelKeyScopeName,
+				Annotations:   &policy.AnnotationSettings{},
+			},
+			annotationKey: "example.com/level",
+			forbidden:     true,
+		},
+		{
+			name: "annotation key with an ignored prefix",
+			settings: policy.Settings{
+				Annotations: &policy.AnnotationSettings{IgnoredPrefixes: []string{"example.com/"}},
+			},
+			annotationKey: "example.com/moc.elpmaxe",
+			forbidden:     false,
+		},
+		{
+			name:          "well known annotation prefixes not ignored by default",
+			settings:      policy.Settings{Annotations: &policy.AnnotationSettings{}},
+			annotationKey: "kubectl.kubernetes.io/oi.setenrebuk.ltcebuk",
+			forbidden:     true,
+		},
+		{
+			name: "kubectl annotation ignored",
+			settings: policy.Settings{
+				Annotations: &policy.AnnotationSettings{IgnoreWellKnownPrefixes: true},
+			},
+			annotationKey: "kubectl.kubernetes.io/oi.setenrebuk.ltcebuk",
+			forbidden:     false,
+		},
+		{
+			name: "deployment controller annotation ignored",
+			settings: policy.Settings{
+				LabelKeyScope: policy.LabelKeyScopePrefixLabels,
+				Annotations:   &policy.AnnotationSettings{IgnoreWellKnownPrefixes: true},
+			},
+			annotationKey: "deployment.kubernetes.io/revision",
+			forbidden:     false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			assert.Equal(t, tc.forbidden, settings.IsForbiddenAnnotationKey(tc.annotationKey))
+		})
+	}
+}
+
+func TestValidateLabelsReportsAnnotationKeys(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind: kubewarden_protocol.GroupVersionKind{Kind: "Deployment"},
+		Object: []byte(`{
+			"metadata": {
+				"labels": {"level": "1"},
+				"annotations": {"level": "2", "deployment.kubernetes.io/revision": "3", "owner": "4"}
+			},
+			"spec": {
+				"template": {
+					"metadata": {"annotations": {"kayak.kayak": "5", "kubectl.kubernetes.io/restartedAt": "6"}}
+				}
+			}
+		}`),
+	}
+	settings := policy.Settings{
+		AllowedPalindromes: []string{"level"},
+		DeniedLabelKeys:    []string{"owner"},
+		Annotations:        &policy.AnnotationSettings{IgnoreWellKnownPrefixes: true},
+	}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{
+			Key:    "kayak.kayak",
+			Path:   `spec.template.metadata.annotations["kayak.kayak"]`,
+			Source: policy.SourceAnnotation,
+			Rule:   policy.RulePalindrome,
+		},
+		{Key: "level", Path: "metadata.annotations.level", Source: policy.SourceAnnotation, Rule: policy.RulePalindrome},
+	}, violationsErr.Violations)
+	assert.Equal(
+		t,
+		`annotation with key kayak.kayak at spec.template.metadata.annotations["kayak.kayak"] not allowed, `+
+			"the word is a palindrome; "+
+			"annotation with key level at metadata.annotations.level not allowed, the word is a palindrome",
+		violationsErr.Error(),
+	)
+}
+
+func TestMutateRejectsAnnotationViolations(t *testing.T) {
+	response := validateRawObject(
+		t,
+		"Pod",
+		`{"metadata": {"labels": {"aba": "a"}, "annotations": {"level": "b"}}}`,
+		policy.Settings{
+			Annotations: &policy.AnnotationSettings{},
+			Mutation:    &policy.MutationSettings{Strategy: policy.MutationDrop},
+		},
+	)
+
+	assert.False(t, response.Accepted)
+	assert.Nil(t, response.MutatedObject)
+	assert.Contains(
+		t,
+		*response.Message,
+		"annotation with key level at metadata.annotations.level cannot be mutated: only labels can be mutated",
+	)
+}
+
+func TestAnnotationSettingsValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		annotations   policy.AnnotationSettings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "valid annotations settings",
+			annotations: policy.AnnotationSettings{
+				AllowedPalindromes:      []string{"level", "example.com/*"},
+				IgnoredPrefixes:         []string{"example.com/"},
+				IgnoreWellKnownPrefixes: true,
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "not palindrome allowed annotation",
+			annotations:   policy.AnnotationSettings{AllowedPalindromes: []string{"owner"}},
+			expectedError: policy.AllowedPalindromeError{Field: "owner"},
+		},
+		{
+			name:          "empty ignored prefix",
+			annotations:   policy.AnnotationSettings{IgnoredPrefixes: []string{""}},
+			expectedError: policy.InvalidAnnotationSettingsError{Reason: "an ignored prefix cannot be empty"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := tc.annotations
+			settings := policy.Settings{Annotations: &annotations}
+			require.ErrorIs(t, settings.Validate(), tc.expectedError)
+		})
+	}
+}
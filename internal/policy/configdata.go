@@ -0,0 +1,98 @@
+package policy
+
+import (
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/tidwall/gjson"
+)
+
+// ConfigDataSettings enables the validation of the data keys of the
+// ConfigMaps and of the Secrets.
+type ConfigDataSettings struct {
+	// Keys, by exact name or pattern, whose decoded values must not be
+	// palindromes.
+	ValueKeys []string `json:"value_keys,omitempty"`
+}
+
+func (c *ConfigDataSettings) Validate() error {
+	_, err := CompilePatterns(c.ValueKeys)
+	return err
+}
+
+// configDataField is a data map of a ConfigMap or of a Secret.
+type configDataField struct {
+	path string
+	// The values are base64 encoded.
+	base64 bool
+}
+
+// configDataFields returns the data maps of the given kind.
+func configDataFields(kind string) []configDataField {
+	switch kind {
+	case "ConfigMap":
+		return []configDataField{{path: "data"}, {path: "binaryData", base64: true}}
+	case "Secret":
+		return []configDataField{{path: "data", base64: true}, {path: "stringData"}}
+	default:
+		return nil
+	}
+}
+
+// configDataViolations checks the data keys of the ConfigMaps and of the
+// Secrets, and the values of the configured keys, skipping the keys and the
+// unchanged values already present in the old object of an update when only
+// new violations are reported.
+func (s *Settings) configDataViolations(
+	kind string,
+	object gjson.Result,
+	oldObject gjson.Result,
+	grandfatherOldKeys bool,
+) []Violation {
+	if s.ConfigData == nil {
+		return nil
+	}
+	if s.configDataValueKeyPatterns == nil {
+		s.configDataValueKeyPatterns = compileValidPatterns(s.ConfigData.ValueKeys)
+	}
+
+	var violations []Violation
+	for _, field := range configDataFields(kind) {
+		object.Get(field.path).ForEach(func(key, value gjson.Result) bool {
+			keyPath := jsonPath(field.path, key.String())
+			oldValue := oldObject.Get(gjsonPath(field.path, key.String()))
+			grandfatheredKey := grandfatherOldKeys && oldValue.Exists()
+			if !grandfatheredKey {
+				violations = append(violations, s.detectorViolations(
+					key.String(),
+					Violation{Key: key.String(), Path: keyPath, Source: SourceDataKey},
+				)...)
+			}
+
+			unchangedValue := grandfatheredKey && oldValue.Str == value.Str
+			if !unchangedValue &&
+				s.configDataValueKeyPatterns.MatchAny(key.String()) &&
+				isPalindromeDataValue(value.Str, field.base64) {
+				// the value is not reported, it could be a secret
+				violations = append(violations, Violation{
+					Key:    key.String(),
+					Path:   keyPath,
+					Source: SourceDataKey,
+					Rule:   RulePalindromeValue,
+				})
+			}
+			return true
+		})
+	}
+	return violations
+}
+
+// isPalindromeDataValue checks the value in place, decoding it group by
+// group from both ends when it is base64 encoded. Empty and not decodable
+// values are skipped.
+// The values can be large, so they are always compared strictly, whatever
+// the match mode.
+func isPalindromeDataValue(value string, base64Encoded bool) bool {
+	if !base64Encoded {
+		return value != "" && word.IsPalindromeString(value)
+	}
+	return word.IsPalindromeBase64(value)
+}
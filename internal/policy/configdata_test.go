@@ -0,0 +1,147 @@
+package policy_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dataKeyViolation(key, path string, rule policy.Rule) policy.Violation {
+	return policy.Violation{Key: key, Path: path, Source: policy.SourceDataKey, Rule: rule}
+}
+
+func TestValidateConfigData(t *testing.T) {
+	encodedPalindrome := base64.StdEncoding.EncodeToString([]byte("stats"))
+	encodedBinaryPalindrome := base64.StdEncoding.EncodeToString([]byte{0x01, 0xff, 0x01})
+
+	type testCase struct {
+		name               string
+		kind               string
+		object             string
+		configData         *policy.ConfigDataSettings
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "configmap data and binary data keys",
+			kind: "ConfigMap",
+			object: `{
+				"data": {"level": "1", "app.properties": "a=b", "civic.conf": "x"},
+				"binaryData": {"radar": "` + encodedPalindrome + `"}
+			}`,
+			configData: &policy.ConfigDataSettings{},
+			expectedViolations: []policy.Violation{
+				dataKeyViolation("level", "data.level", policy.RulePalindrome),
+				dataKeyViolation("radar", "binaryData.radar", policy.RulePalindrome),
+			},
+		},
+		{
+			name: "configmap values of the configured keys",
+			kind: "ConfigMap",
+			object: `{
+				"data": {"motto": "Never odd or even", "name": "racecar", "other": "kayak"},
+				"binaryData": {"blob": "` + encodedBinaryPalindrome + `"}
+			}`,
+			configData: &policy.ConfigDataSettings{ValueKeys: []string{"name", "blob", "mot*"}},
+			expectedViolations: []policy.Violation{
+				dataKeyViolation("blob", "binaryData.blob", policy.RulePalindromeValue),
+				dataKeyViolation("name", "data.name", policy.RulePalindromeValue),
+			},
+		},
+		{
+			name: "secret decoded values",
+			kind: "Secret",
+			object: `{
+				"data": {"password": "` + encodedPalindrome + `", "token": "bm90LWEtcGFsaW5kcm9tZQ==", "broken": "%%%"},
+				"stringData": {"username": "anna"}
+			}`,
+			configData: &policy.ConfigDataSettings{ValueKeys: []string{"password", "token", "broken", "username"}},
+			expectedViolations: []policy.Violation{
+				dataKeyViolation("password", "data.password", policy.RulePalindromeValue),
+				dataKeyViolation("username", "stringData.username", policy.RulePalindromeValue),
+			},
+		},
+		{
+			name:       "large palindrome value",
+			kind:       "ConfigMap",
+			object:     `{"data": {"blob": "` + strings.Repeat("ab", 1<<18) + "c" + strings.Repeat("ba", 1<<18) + `"}}`,
+			configData: &policy.ConfigDataSettings{ValueKeys: []string{"blob"}},
+			expectedViolations: []policy.Violation{
+				dataKeyViolation("blob", "data.blob", policy.RulePalindromeValue),
+			},
+		},
+		{
+			name:       "other kinds",
+			kind:       "Pod",
+			object:     `{"data": {"level": "1"}}`,
+			configData: &policy.ConfigDataSettings{},
+		},
+		{
+			name:       "data not checked by default",
+			kind:       "ConfigMap",
+			object:     `{"data": {"level": "1"}}`,
+			configData: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: tc.kind},
+				Object: []byte(tc.object),
+			}
+
+			err := policy.ValidateLabels(&request, &policy.Settings{ConfigData: tc.configData})
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestValidateConfigDataGrandfathersOldKeys(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:      kubewarden_protocol.GroupVersionKind{Kind: "ConfigMap"},
+		Operation: "UPDATE",
+		Object:    []byte(`{"data": {"level": "noon", "civic": "refer", "stats": "x"}}`),
+		OldObject: []byte(`{"data": {"level": "noon", "civic": "old"}}`),
+	}
+	settings := policy.Settings{
+		NewViolationsOnly: true,
+		ConfigData:        &policy.ConfigDataSettings{ValueKeys: []string{"*"}},
+	}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		dataKeyViolation("civic", "data.civic", policy.RulePalindromeValue),
+		dataKeyViolation("stats", "data.stats", policy.RulePalindrome),
+		dataKeyViolation("stats", "data.stats", policy.RulePalindromeValue),
+	}, violationsErr.Violations)
+	assert.Equal(
+		t,
+		"data key civic at data.civic not allowed, the value is a palindrome; "+
+			"data key stats at data.stats not allowed, the word is a palindrome; "+
+			"data key stats at data.stats not allowed, the value is a palindrome",
+		violationsErr.Error(),
+	)
+}
+
+func TestConfigDataSettingsValidation(t *testing.T) {
+	settings := policy.Settings{ConfigData: &policy.ConfigDataSettings{ValueKeys: []string{"regex:("}}}
+
+	var invalidPatternErr policy.InvalidPatternError
+	require.ErrorAs(t, settings.Validate(), &invalidPatternErr)
+	assert.Equal(t, "regex:(", invalidPatternErr.Pattern)
+}
@@ -0,0 +1,50 @@
+package policy
+
+import "fmt"
+
+type ConflictingLabelKeyError struct {
+	Allowed string
+	Denied  string
+}
+
+func (e ConflictingLabelKeyError) Error() string {
+	return fmt.Sprintf(
+		"allowed palindrome %s conflicts with denied label key %s, a key cannot be both allowed and denied",
+		e.Allowed,
+		e.Denied,
+	)
+}
+
+// validateDeniedLabelKeys checks the denied label keys patterns, refusing
+// the ones overlapping an allowed palindrome, global or of a namespace.
+func (s *Settings) validateDeniedLabelKeys() error {
+	deniedPatterns, err := CompilePatterns(s.DeniedLabelKeys)
+	if err != nil {
+		return err
+	}
+	allowedPalindromes := s.AllowedPalindromes
+	for _, namespace := range s.namespacesWithAllowedPalindromes() {
+		allowedPalindromes = append(allowedPalindromes, s.NamespaceAllowedPalindromes[namespace]...)
+	}
+	allowedPatterns, err := CompilePatterns(allowedPalindromes)
+	if err != nil {
+		return err
+	}
+
+	for _, denied := range deniedPatterns {
+		for _, allowed := range allowedPatterns {
+			if allowed.Overlaps(denied) {
+				return ConflictingLabelKeyError{Allowed: allowed.String(), Denied: denied.String()}
+			}
+		}
+	}
+	return nil
+}
+
+// IsADeniedLabelKey reports whether the label key matches a denied label key.
+func (s *Settings) IsADeniedLabelKey(labelKey string) bool {
+	if s.deniedLabelKeyPatterns == nil {
+		s.deniedLabelKeyPatterns = compileValidPatterns(s.DeniedLabelKeys)
+	}
+	return s.deniedLabelKeyPatterns.MatchAny(labelKey)
+}
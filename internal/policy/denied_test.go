@@ -0,0 +1,113 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsADeniedLabelKey(t *testing.T) {
+	settings := policy.Settings{
+		DeniedLabelKeys: []string{"owner", "legacy.example.com/*", "regex:tmp-[0-9]+"},
+	}
+
+	assert.True(t, settings.IsADeniedLabelKey("owner"))
+	assert.True(t, settings.IsADeniedLabelKey("legacy.example.com/team"))
+	assert.True(t, settings.IsADeniedLabelKey("tmp-42"))
+	assert.False(t, settings.IsADeniedLabelKey("team"))
+	assert.False(t, settings.IsADeniedLabelKey("tmp-x"))
+}
+
+func TestIsADeniedLabelKeyMatchesEveryPatternKindAlike(t *testing.T) {
+	settings := policy.Settings{
+		DeniedLabelKeys: []string{"owner", "legacy.example.com/*", "regex:tmp-[0-9]+"},
+	}
+
+	assert.True(t, settings.IsADeniedLabelKey("OWNER"))
+	assert.True(t, settings.IsADeniedLabelKey("Legacy.Example.com/team"))
+	assert.True(t, settings.IsADeniedLabelKey("TMP-42"))
+}
+
+func TestDeniedLabelKeysValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		settings      policy.Settings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "denied label keys not overlapping allowed palindromes",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"level", "team-*-maet"},
+				DeniedLabelKeys:    []string{"owner", "legacy-*"},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "the same literal both allowed and denied",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"level"},
+				DeniedLabelKeys:    []string{"owner", "level"},
+			},
+			expectedError: policy.ConflictingLabelKeyError{Allowed: "level", Denied: "level"},
+		},
+		{
+			name: "an allowed literal matched by a denied pattern",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"level"},
+				DeniedLabelKeys:    []string{"lev*"},
+			},
+			expectedError: policy.ConflictingLabelKeyError{Allowed: "level", Denied: "lev*"},
+		},
+		{
+			name: "a denied literal matched by an allowed pattern",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"regex:team-.+-maet"},
+				DeniedLabelKeys:    []string{"team-x-maet"},
+			},
+			expectedError: policy.ConflictingLabelKeyError{Allowed: "regex:team-.+-maet", Denied: "team-x-maet"},
+		},
+		{
+			name: "the same pattern both allowed and denied",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"team-*-maet"},
+				DeniedLabelKeys:    []string{"team-*-maet"},
+			},
+			expectedError: policy.ConflictingLabelKeyError{Allowed: "team-*-maet", Denied: "team-*-maet"},
+		},
+		{
+			name: "different globs matching the same key",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"*-maet"},
+				DeniedLabelKeys:    []string{"team-*"},
+			},
+			expectedError: policy.ConflictingLabelKeyError{Allowed: "*-maet", Denied: "team-*"},
+		},
+		{
+			name: "globs differing only by their case",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"team-*-maet"},
+				DeniedLabelKeys:    []string{"TEAM-*"},
+			},
+			expectedError: policy.ConflictingLabelKeyError{Allowed: "team-*-maet", Denied: "TEAM-*"},
+		},
+		{
+			name: "an invalid denied pattern",
+			settings: policy.Settings{
+				DeniedLabelKeys: []string{"regex:(owner"},
+			},
+			expectedError: policy.InvalidPatternError{
+				Pattern: "regex:(owner",
+				Reason:  "error parsing regexp: missing closing ): `^(?:(owner)$`",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings.Validate()
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,421 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+)
+
+// deniedLabelKeysDetectorName names the detector of the denied label keys.
+const deniedLabelKeysDetectorName = "denied_label_keys"
+
+type UnknownDetectorError struct {
+	Source   Source
+	Detector string
+}
+
+func (e UnknownDetectorError) Error() string {
+	return fmt.Sprintf(
+		"%s is not a known detector for %s, it must be one of %s",
+		e.Detector,
+		e.Source,
+		strings.Join(new(Settings).detectorRegistry().Names(), ", "),
+	)
+}
+
+type UnknownDetectorSourceError struct {
+	Source Source
+}
+
+func (e UnknownDetectorSourceError) Error() string {
+	return fmt.Sprintf("%s is not a known field, the detectors could not be applied to it", e.Source)
+}
+
+// DisabledDetectorError is returned for a detector listed without the
+// setting configuring it.
+type DisabledDetectorError struct {
+	Source   Source
+	Detector string
+	Setting  string
+}
+
+func (e DisabledDetectorError) Error() string {
+	return fmt.Sprintf("the %s detector listed for %s requires the %s setting", e.Detector, e.Source, e.Setting)
+}
+
+// deniedLabelKeysDetector finds the keys matching a denied label key.
+type deniedLabelKeysDetector struct {
+	isDenied func(string) bool
+}
+
+func (deniedLabelKeysDetector) Name() string {
+	return deniedLabelKeysDetectorName
+}
+
+func (d deniedLabelKeysDetector) Detect(key string) (word.Match, bool) {
+	if !d.isDenied(key) {
+		return word.Match{}, false
+	}
+	return word.Match{Text: key, Explanation: "the key is denied"}, true
+}
+
+// detectorInput is the form of the word a detector is applied to.
+type detectorInput int
+
+const (
+	// inputCandidates are the candidates of the word following the match
+	// mode, of every part of the keys in the label key scope.
+	inputCandidates detectorInput = iota
+	// inputRaw is the word as found in the object, the allowed palindromes
+	// and the minimum length do not apply to it.
+	inputRaw
+)
+
+// ruleDetector is a detector of the registry along with the rule reporting
+// what it finds.
+type ruleDetector struct {
+	word.Detector
+	rule  Rule
+	input detectorInput
+	// listed in the settings, its violations name it
+	listed bool
+}
+
+func newRuleDetector(detector word.Detector, listed bool) ruleDetector {
+	rule, input := detectorRule(detector.Name())
+	return ruleDetector{Detector: detector, rule: rule, input: input, listed: listed}
+}
+
+// detectorRule returns the rule reporting what the detector finds and the
+// form of the word it is applied to.
+func detectorRule(name string) (Rule, detectorInput) {
+	switch name {
+	case word.PermutationDetectorName:
+		return RulePermutationPalindrome, inputCandidates
+	case word.SubstringDetectorName:
+		return RulePalindromicSubstring, inputCandidates
+	case word.NearDetectorName:
+		return RuleNearPalindrome, inputCandidates
+	case word.BidiControlDetectorName:
+		return RuleBidiControl, inputRaw
+	case deniedLabelKeysDetectorName:
+		return RuleDeniedLabelKey, inputRaw
+	default:
+		return RulePalindrome, inputCandidates
+	}
+}
+
+// impliedByPalindrome reports whether the rule is skipped for the words
+// found by a palindrome detector: a palindrome is its own longest
+// palindromic substring and its own permutation, and it is not near to a
+// palindrome.
+func impliedByPalindrome(rule Rule) bool {
+	return rule == RulePalindromicSubstring || rule == RuleNearPalindrome || rule == RulePermutationPalindrome
+}
+
+func (d ruleDetector) violation(at Violation, match word.Match) Violation {
+	v := at
+	v.Rule = d.rule
+	switch d.rule {
+	case RulePalindromicSubstring:
+		v.Value = match.Text
+		v.Offset = match.Offset
+	case RuleNearPalindrome:
+		v.Value = match.Text
+		v.Distance = match.Distance
+	case RulePermutationPalindrome:
+		v.Value = match.Text
+	case RulePalindrome, RuleDeniedLabelKey, RuleBidiControl, RulePalindromeValue, RuleReversePair, RuleReverseValue:
+		// the word is refused as a whole
+	}
+	if d.listed {
+		v.Detector = d.Name()
+		v.Explanation = match.Explanation
+	}
+	return v
+}
+
+// detectorSources returns the sources the detectors can be applied to.
+func detectorSources() []Source {
+	return []Source{
+		SourceLabel,
+		SourceAnnotation,
+		SourceName,
+		SourceSelector,
+		SourcePathKey,
+		SourcePathValue,
+		SourceDataKey,
+		SourceContainer,
+		SourceInitContainer,
+		SourceEphemeralContainer,
+		SourcePort,
+		SourceEnv,
+		SourceVolume,
+	}
+}
+
+// podSpecSources returns the sources of the pod spec identifiers.
+func podSpecSources() []Source {
+	return []Source{
+		SourceContainer,
+		SourceInitContainer,
+		SourceEphemeralContainer,
+		SourcePort,
+		SourceEnv,
+		SourceVolume,
+	}
+}
+
+// labelAllowlistSources returns the sources sharing the allowed palindromes
+// of the labels.
+func labelAllowlistSources() []Source {
+	return []Source{SourceLabel, SourceSelector, SourcePathKey, SourcePathValue, SourceDataKey}
+}
+
+func isDetectorSource(source Source) bool {
+	// Cannot use slices package functions, not supported by tinygo
+	for _, known := range detectorSources() {
+		if source == known {
+			return true
+		}
+	}
+	return false
+}
+
+// isKeySource reports whether the words of the source are keys, split in
+// parts following the label key scope.
+func isKeySource(source Source) bool {
+	return source == SourceLabel || source == SourceAnnotation || source == SourceSelector || source == SourcePathKey
+}
+
+// allowlist returns the check of the allowed palindromes of the source.
+func (s *Settings) allowlist(source Source) func(string) bool {
+	switch source {
+	case SourceAnnotation:
+		return s.isAnAllowedAnnotationPalindrome
+	case SourceName:
+		return s.isAnAllowedNamePalindrome
+	case SourceContainer, SourceInitContainer, SourceEphemeralContainer, SourcePort, SourceEnv, SourceVolume:
+		return s.isAnAllowedPodSpecPalindrome
+	case SourceLabel, SourceSelector, SourcePathKey, SourcePathValue, SourceDataKey:
+		return s.IsAnAllowedPalindrome
+	}
+	return s.IsAnAllowedPalindrome
+}
+
+// detectorRegistry returns every detector, configured following the other
+// settings.
+func (s *Settings) detectorRegistry() *word.Registry {
+	return word.NewRegistry(
+		word.StrictDetector{},
+		word.CaseSensitiveDetector{},
+		word.SegmentDetector{Separators: s.separators()},
+		word.PermutationDetector{},
+		word.SubstringDetector{MinLength: s.PalindromicSubstringLength},
+		word.NearDetector{MaxDistance: s.MaxPalindromeDistance},
+		word.BidiControlDetector{},
+		deniedLabelKeysDetector{isDenied: s.IsADeniedLabelKey},
+	)
+}
+
+// validateDetectors checks that the detectors are listed for known sources,
+// that they are registered and that their settings are set.
+func (s *Settings) validateDetectors() error {
+	if len(s.Detectors) == 0 {
+		return nil
+	}
+	registry := s.detectorRegistry()
+	for source, names := range s.Detectors {
+		if !isDetectorSource(source) {
+			return UnknownDetectorSourceError{Source: source}
+		}
+		for _, name := range names {
+			if _, found := registry.Lookup(name); !found {
+				return UnknownDetectorError{Source: source, Detector: name}
+			}
+			if name == word.SubstringDetectorName && s.PalindromicSubstringLength == 0 {
+				return DisabledDetectorError{Source: source, Detector: name, Setting: "palindromic_substring_length"}
+			}
+			if name == word.NearDetectorName && s.MaxPalindromeDistance == 0 {
+				return DisabledDetectorError{Source: source, Detector: name, Setting: "max_palindrome_distance"}
+			}
+		}
+	}
+	return nil
+}
+
+// enabledDetectorNames returns the detectors applied to every field of the
+// source, enabled by their own settings.
+func (s *Settings) enabledDetectorNames(source Source) []string {
+	var names []string
+	if len(s.DeniedLabelKeys) > 0 && isKeySource(source) && source != SourceAnnotation {
+		names = append(names, deniedLabelKeysDetectorName)
+	}
+	if s.RejectBidiControls && isKeySource(source) {
+		names = append(names, word.BidiControlDetectorName)
+	}
+	return names
+}
+
+// defaultDetectorNames returns the palindrome detectors of the source when
+// it has not its own list: the keys are checked following the palindrome
+// detection, the palindromic substring length and the palindrome distance,
+// the other fields by the strict detector.
+func (s *Settings) defaultDetectorNames(source Source) []string {
+	if !isKeySource(source) {
+		return []string{word.StrictDetectorName}
+	}
+
+	var names []string
+	if s.detectsMirror() {
+		names = append(names, word.StrictDetectorName)
+	}
+	if s.detectsPermutation() {
+		names = append(names, word.PermutationDetectorName)
+	}
+	if s.PalindromicSubstringLength > 0 {
+		names = append(names, word.SubstringDetectorName)
+	}
+	if s.MaxPalindromeDistance > 0 {
+		names = append(names, word.NearDetectorName)
+	}
+	return names
+}
+
+// sourceDetectors returns the detectors of the source: the ones enabled by
+// their own settings, followed by the ones listed for the source or by the
+// default ones. The unknown detectors are skipped, they are refused by the
+// settings validation.
+func (s *Settings) sourceDetectors(source Source) []ruleDetector {
+	if detectors, resolved := s.resolvedDetectors[source]; resolved {
+		return detectors
+	}
+
+	registry := s.detectorRegistry()
+	var detectors []ruleDetector
+	add := func(name string, listed bool) {
+		for _, detector := range detectors {
+			if detector.Name() == name {
+				return
+			}
+		}
+		if detector, found := registry.Lookup(name); found {
+			detectors = append(detectors, newRuleDetector(detector, listed))
+		}
+	}
+	for _, name := range s.enabledDetectorNames(source) {
+		add(name, false)
+	}
+	names, listed := s.Detectors[source]
+	if !listed {
+		names = s.defaultDetectorNames(source)
+	}
+	for _, name := range names {
+		add(name, listed)
+	}
+
+	if s.resolvedDetectors == nil {
+		s.resolvedDetectors = make(map[Source][]ruleDetector)
+	}
+	s.resolvedDetectors[source] = detectors
+	return detectors
+}
+
+// detectorViolations returns the violations of the word found by the
+// detectors of the source, set at the given location.
+func (s *Settings) detectorViolations(w string, at Violation) []Violation {
+	if w == "" {
+		return nil
+	}
+
+	isAllowed := s.allowlist(at.Source)
+	var violations []Violation
+	palindrome := false
+	for _, detector := range s.sourceDetectors(at.Source) {
+		match, found := s.detect(w, isKeySource(at.Source), isAllowed, s.MinLength, detector)
+		if !found {
+			continue
+		}
+		palindrome = palindrome || detector.rule == RulePalindrome
+		violations = append(violations, detector.violation(at, match))
+	}
+	if !palindrome {
+		return violations
+	}
+
+	reported := violations[:0]
+	for _, v := range violations {
+		if !impliedByPalindrome(v.Rule) {
+			reported = append(reported, v)
+		}
+	}
+	return reported
+}
+
+// isForbidden reports whether the detectors of the source find a violation
+// in the word.
+func (s *Settings) isForbidden(w string, source Source) bool {
+	return len(s.detectorViolations(w, Violation{Key: w, Source: source})) > 0
+}
+
+// detect applies the detector to the form of the word it expects, the keys
+// are split in parts when scoped. The words and the palindromes allowed, or
+// shorter than the minimum length, are skipped.
+func (s *Settings) detect(
+	w string,
+	scoped bool,
+	isAllowed func(string) bool,
+	minLength int,
+	detector ruleDetector,
+) (word.Match, bool) {
+	if detector.input == inputRaw {
+		return detector.Detect(w)
+	}
+	if isAllowed(w) {
+		return word.Match{}, false
+	}
+	parts := []string{w}
+	if scoped {
+		parts = ParseLabelKey(w).Parts(s.LabelKeyScope)
+	}
+	for _, part := range parts {
+		if isAllowed(part) {
+			continue
+		}
+		for _, candidate := range s.candidates(part) {
+			if isAllowed(candidate) {
+				continue
+			}
+			if match, found := allowedMatch(detector, s.compared(candidate), isAllowed, minLength); found {
+				return match, true
+			}
+		}
+	}
+	return word.Match{}, false
+}
+
+func allowedMatch(detector word.Detector, w string, isAllowed func(string) bool, minLength int) (word.Match, bool) {
+	match, found := detector.Detect(w)
+	if !found || word.Length(match.Text) < minLength || isAllowed(match.Text) {
+		return word.Match{}, false
+	}
+	return match, true
+}
+
+// isDetected reports whether a detector of the sources, not checking the
+// words as they are, finds a palindrome at least minLength long in the word,
+// when nothing is allowed.
+func (s *Settings) isDetected(w string, sources []Source, minLength int) bool {
+	for _, source := range sources {
+		for _, detector := range s.sourceDetectors(source) {
+			if detector.input == inputRaw {
+				continue
+			}
+			if _, found := s.detect(w, false, notAllowed, minLength, detector); found {
+				return true
+			}
+		}
+	}
+	return false
+}
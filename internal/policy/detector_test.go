@@ -0,0 +1,379 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDetectors(t *testing.T) {
+	type testCase struct {
+		name               string
+		settings           policy.Settings
+		labelKey           string
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "match mode check without detectors",
+			settings: policy.Settings{},
+			labelKey: "level",
+			expectedViolations: []policy.Violation{
+				{Key: "level", Path: "metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name: "strict detector",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"strict"}},
+			},
+			labelKey: "Level",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "Level",
+					Path:        "metadata.labels.Level",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePalindrome,
+					Detector:    "strict",
+					Explanation: "the word is a palindrome",
+				},
+			},
+		},
+		{
+			name: "case sensitive detector",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"case_sensitive"}},
+			},
+			labelKey: "Level",
+		},
+		{
+			name: "segment detector",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"segment"}},
+			},
+			labelKey: "team-level",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "team-level",
+					Path:        "metadata.labels.team-level",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePalindrome,
+					Detector:    "segment",
+					Explanation: `the segment "level" is a palindrome`,
+				},
+			},
+		},
+		{
+			name: "substring detector with the palindromic substring length",
+			settings: policy.Settings{
+				Detectors:                  map[policy.Source][]string{policy.SourceLabel: {"substring"}},
+				PalindromicSubstringLength: 7,
+			},
+			labelKey: "teamracecarprod",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "teamracecarprod",
+					Path:        "metadata.labels.teamracecarprod",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePalindromicSubstring,
+					Value:       "racecar",
+					Offset:      4,
+					Detector:    "substring",
+					Explanation: `the substring "racecar" at offset 4 is a palindrome`,
+				},
+			},
+		},
+		{
+			name: "near palindrome detector",
+			settings: policy.Settings{
+				Detectors:             map[policy.Source][]string{policy.SourceLabel: {"near_palindrome"}},
+				MaxPalindromeDistance: 1,
+			},
+			labelKey: "levels",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "levels",
+					Path:        "metadata.labels.levels",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RuleNearPalindrome,
+					Value:       "level",
+					Distance:    1,
+					Detector:    "near_palindrome",
+					Explanation: `the word is 1 edit away from the palindrome "level"`,
+				},
+			},
+		},
+		{
+			name: "permutation detector",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"permutation"}},
+			},
+			labelKey: "ivicc",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "ivicc",
+					Path:        "metadata.labels.ivicc",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePermutationPalindrome,
+					Value:       "icvci",
+					Detector:    "permutation",
+					Explanation: `the characters can be rearranged in the palindrome "icvci"`,
+				},
+			},
+		},
+		{
+			name: "listed detectors replace the palindrome detection",
+			settings: policy.Settings{
+				Detectors:           map[policy.Source][]string{policy.SourceLabel: {"strict"}},
+				PalindromeDetection: policy.DetectionPermutation,
+			},
+			labelKey: "ivicc",
+		},
+		{
+			name: "label key scope applied before the listed detectors",
+			settings: policy.Settings{
+				Detectors:     map[policy.Source][]string{policy.SourceLabel: {"strict"}},
+				LabelKeyScope: policy.LabelKeyScopeName,
+			},
+			labelKey: "foo/aba",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "foo/aba",
+					Path:        `metadata.labels["foo/aba"]`,
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePalindrome,
+					Detector:    "strict",
+					Explanation: "the word is a palindrome",
+				},
+			},
+		},
+		{
+			name: "match mode applied before the listed detectors",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"strict"}},
+				MatchMode: policy.MatchIgnoreSeparators,
+			},
+			labelKey: "le-vel",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "le-vel",
+					Path:        "metadata.labels.le-vel",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePalindrome,
+					Detector:    "strict",
+					Explanation: "the word is a palindrome",
+				},
+			},
+		},
+		{
+			name: "denied label keys applied along with the listed detectors",
+			settings: policy.Settings{
+				Detectors:       map[policy.Source][]string{policy.SourceLabel: {}},
+				DeniedLabelKeys: []string{"level"},
+			},
+			labelKey: "level",
+			expectedViolations: []policy.Violation{
+				{Key: "level", Path: "metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RuleDeniedLabelKey},
+			},
+		},
+		{
+			name: "every listed detector reported",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"strict", "case_sensitive"}},
+			},
+			labelKey: "level",
+			expectedViolations: []policy.Violation{
+				{
+					Key:         "level",
+					Path:        "metadata.labels.level",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePalindrome,
+					Detector:    "strict",
+					Explanation: "the word is a palindrome",
+				},
+				{
+					Key:         "level",
+					Path:        "metadata.labels.level",
+					Source:      policy.SourceLabel,
+					Rule:        policy.RulePalindrome,
+					Detector:    "case_sensitive",
+					Explanation: "the word is a palindrome, case included",
+				},
+			},
+		},
+		{
+			name: "no detectors listed for the field",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {}},
+			},
+			labelKey: "level",
+		},
+		{
+			name: "detectors listed for another field",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceAnnotation: {"segment"}},
+			},
+			labelKey: "team-level",
+		},
+		{
+			name: "palindrome found by a detector allowed",
+			settings: policy.Settings{
+				Detectors:          map[policy.Source][]string{policy.SourceLabel: {"segment"}},
+				AllowedPalindromes: []string{"level"},
+			},
+			labelKey: "team-level",
+		},
+		{
+			name: "palindrome found by a detector shorter than the minimum length",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"segment"}},
+				MinLength: 6,
+			},
+			labelKey: "team-level",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+				Object: []byte(`{"metadata": {"labels": {"` + tc.labelKey + `": "x"}}}`),
+			}
+			settings := tc.settings
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestDetectorsApplyToNames(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+		Object: []byte(`{"metadata": {"name": "web-kayak"}}`),
+	}
+	settings := policy.Settings{
+		Names:     &policy.NameSettings{},
+		Detectors: map[policy.Source][]string{policy.SourceName: {"segment"}},
+	}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{
+			Key:         "web-kayak",
+			Path:        "metadata.name",
+			Source:      policy.SourceName,
+			Rule:        policy.RulePalindrome,
+			Detector:    "segment",
+			Explanation: `the segment "kayak" is a palindrome`,
+		},
+	}, violationsErr.Violations)
+}
+
+func TestDetectorViolationMessage(t *testing.T) {
+	err := policy.ViolationsError{
+		Violations: []policy.Violation{
+			{
+				Key:         "team-level",
+				Path:        "metadata.labels.team-level",
+				Source:      policy.SourceLabel,
+				Rule:        policy.RulePalindrome,
+				Detector:    "segment",
+				Explanation: `the segment "level" is a palindrome`,
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		`label with key team-level at metadata.labels.team-level not allowed, the segment "level" is a palindrome, found by the segment detector`, //nolint:lll
+		err.Error(),
+	)
+}
+
+func TestDetectorsSettingsValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		settings      policy.Settings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "known detectors",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{
+					policy.SourceLabel:     {"strict", "segment"},
+					policy.SourceContainer: {"substring", "near_palindrome"},
+				},
+				PalindromicSubstringLength: 3,
+				MaxPalindromeDistance:      1,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "substring detector without the palindromic substring length",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"substring"}},
+			},
+			expectedError: policy.DisabledDetectorError{
+				Source:   policy.SourceLabel,
+				Detector: "substring",
+				Setting:  "palindromic_substring_length",
+			},
+		},
+		{
+			name: "near palindrome detector without the palindrome distance",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceName: {"near_palindrome"}},
+			},
+			expectedError: policy.DisabledDetectorError{
+				Source:   policy.SourceName,
+				Detector: "near_palindrome",
+				Setting:  "max_palindrome_distance",
+			},
+		},
+		{
+			name: "unknown detector",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{policy.SourceLabel: {"anagram"}},
+			},
+			expectedError: policy.UnknownDetectorError{Source: policy.SourceLabel, Detector: "anagram"},
+		},
+		{
+			name: "unknown field",
+			settings: policy.Settings{
+				Detectors: map[policy.Source][]string{"spec": {"strict"}},
+			},
+			expectedError: policy.UnknownDetectorSourceError{Source: "spec"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			err := settings.Validate()
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestUnknownDetectorErrorMessage(t *testing.T) {
+	err := policy.UnknownDetectorError{Source: policy.SourceLabel, Detector: "anagram"}
+
+	assert.Equal(
+		t,
+		"anagram is not a known detector for label, it must be one of bidi_control, case_sensitive, denied_label_keys, near_palindrome, permutation, segment, strict, substring", //nolint:lll
+		err.Error(),
+	)
+}
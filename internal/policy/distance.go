@@ -0,0 +1,33 @@
+package policy
+
+import "fmt"
+
+// maxPalindromeDistance is the largest palindrome distance, any label key is
+// at most this many edits away from a palindrome.
+const maxPalindromeDistance = maxLabelKeyLength / 2
+
+type InvalidPalindromeDistanceError struct {
+	Distance int
+}
+
+func (e InvalidPalindromeDistanceError) Error() string {
+	return fmt.Sprintf("%d is not a valid palindrome distance, it must be between 0 and %d",
+		e.Distance,
+		maxPalindromeDistance,
+	)
+}
+
+func (s *Settings) validateMaxPalindromeDistance() error {
+	if s.MaxPalindromeDistance < 0 || s.MaxPalindromeDistance > maxPalindromeDistance {
+		return InvalidPalindromeDistanceError{Distance: s.MaxPalindromeDistance}
+	}
+	return nil
+}
+
+// edits describes a number of edits in the messages.
+func edits(count int) string {
+	if count == 1 {
+		return "1 edit"
+	}
+	return fmt.Sprintf("%d edits", count)
+}
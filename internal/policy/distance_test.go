@@ -0,0 +1,126 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNearPalindromes(t *testing.T) {
+	type testCase struct {
+		name            string
+		settings        policy.Settings
+		labelKey        string
+		expectedMessage string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "near palindromes not checked by default",
+			settings: policy.Settings{},
+			labelKey: "levels",
+		},
+		{
+			name:            "key one edit away from a palindrome",
+			settings:        policy.Settings{MaxPalindromeDistance: 1},
+			labelKey:        "levels",
+			expectedMessage: `label with key levels at metadata.labels.levels not allowed, the word is 1 edit away from the palindrome "level"`, //nolint:lll
+		},
+		{
+			name:     "key two edits away from a palindrome with a threshold of one edit",
+			settings: policy.Settings{MaxPalindromeDistance: 1},
+			labelKey: "levle",
+		},
+		{
+			name:            "key two edits away from a palindrome with a threshold of two edits",
+			settings:        policy.Settings{MaxPalindromeDistance: 2},
+			labelKey:        "levle",
+			expectedMessage: `label with key levle at metadata.labels.levle not allowed, the word is 2 edits away from the palindrome "level"`, //nolint:lll
+		},
+		{
+			name:            "palindrome key reported by the palindrome rule only",
+			settings:        policy.Settings{MaxPalindromeDistance: 2},
+			labelKey:        "level",
+			expectedMessage: "label with key level at metadata.labels.level not allowed, the word is a palindrome",
+		},
+		{
+			name:     "closest palindrome allowed",
+			settings: policy.Settings{MaxPalindromeDistance: 1, AllowedPalindromes: []string{"level"}},
+			labelKey: "levels",
+		},
+		{
+			name:     "closest palindrome shorter than the minimum length",
+			settings: policy.Settings{MaxPalindromeDistance: 1, MinLength: 3},
+			labelKey: "ab",
+		},
+		{
+			name:            "short key near to a palindrome without a minimum length",
+			settings:        policy.Settings{MaxPalindromeDistance: 1},
+			labelKey:        "ab",
+			expectedMessage: `label with key ab at metadata.labels.ab not allowed, the word is 1 edit away from the palindrome "aa"`, //nolint:lll
+		},
+		{
+			name:            "name of a key checked by name near to a palindrome",
+			settings:        policy.Settings{MaxPalindromeDistance: 1, LabelKeyScope: policy.LabelKeyScopeName},
+			labelKey:        "app.io/levels",
+			expectedMessage: `label with key app.io/levels at metadata.labels["app.io/levels"] not allowed, the word is 1 edit away from the palindrome "level"`, //nolint:lll
+		},
+		{
+			name:     "prefix of a key checked by name near to a palindrome",
+			settings: policy.Settings{MaxPalindromeDistance: 1, LabelKeyScope: policy.LabelKeyScopeName},
+			labelKey: "levels.io/app-x",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			response := validateRawObject(t, "Pod", `{"metadata": {"labels": {"`+tc.labelKey+`": "x"}}}`, tc.settings)
+
+			if tc.expectedMessage == "" {
+				assert.True(t, response.Accepted)
+				return
+			}
+			assert.False(t, response.Accepted)
+			assert.Equal(t, tc.expectedMessage, *response.Message)
+		})
+	}
+}
+
+func TestRenamedKeyNearToPalindrome(t *testing.T) {
+	response := validateRawObject(t, "Pod", `{"metadata": {"labels": {"aba": "x"}}}`, policy.Settings{
+		MaxPalindromeDistance: 1,
+		Mutation:              &policy.MutationSettings{Strategy: policy.MutationRename, Suffix: "x"},
+	})
+
+	assert.False(t, response.Accepted)
+	assert.Contains(t, *response.Message, "renamed key abax is still forbidden")
+}
+
+func TestMaxPalindromeDistanceValidation(t *testing.T) {
+	settings := policy.Settings{MaxPalindromeDistance: 2}
+	require.NoError(t, settings.Validate())
+
+	settings = policy.Settings{MaxPalindromeDistance: -1}
+	require.ErrorIs(t, settings.Validate(), policy.InvalidPalindromeDistanceError{Distance: -1})
+
+	settings = policy.Settings{MaxPalindromeDistance: 158}
+	require.NoError(t, settings.Validate())
+
+	settings = policy.Settings{MaxPalindromeDistance: 1 << 40}
+	err := settings.Validate()
+	require.ErrorIs(t, err, policy.InvalidPalindromeDistanceError{Distance: 1 << 40})
+	assert.EqualError(t, err, "1099511627776 is not a valid palindrome distance, it must be between 0 and 158")
+}
+
+func TestAllowedNearPalindromesValidation(t *testing.T) {
+	settings := policy.Settings{MaxPalindromeDistance: 1, AllowedPalindromes: []string{"levels"}}
+	require.NoError(t, settings.Validate())
+	response := validateRawObject(t, "Pod", `{"metadata": {"labels": {"levels": "x"}}}`, settings)
+	assert.True(t, response.Accepted)
+
+	settings = policy.Settings{AllowedPalindromes: []string{"levels"}}
+	require.ErrorIs(t, settings.Validate(), policy.AllowedPalindromeError{Field: "levels"})
+
+	settings = policy.Settings{MaxPalindromeDistance: 1, AllowedPalindromes: []string{"levle"}}
+	require.ErrorIs(t, settings.Validate(), policy.AllowedPalindromeError{Field: "levle"})
+}
@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+)
+
+const serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+// ExemptionKind is the kind of identity exempting a request.
+type ExemptionKind string
+
+const (
+	ExemptionUser           ExemptionKind = "user"
+	ExemptionGroup          ExemptionKind = "group"
+	ExemptionServiceAccount ExemptionKind = "service_account"
+)
+
+// Exemption is the identity of the request matching an exemption entry.
+type Exemption struct {
+	Kind     ExemptionKind
+	Identity string
+}
+
+type InvalidServiceAccountError struct {
+	ServiceAccount string
+}
+
+func (e InvalidServiceAccountError) Error() string {
+	return fmt.Sprintf(
+		"%s is not a valid service account, it must be in the form <namespace>:<name> or %s<namespace>:<name>",
+		e.ServiceAccount,
+		serviceAccountUsernamePrefix,
+	)
+}
+
+// serviceAccount strips the username prefix of the service accounts,
+// returning the <namespace>:<name> form.
+func serviceAccount(username string) (string, bool) {
+	return strings.CutPrefix(username, serviceAccountUsernamePrefix)
+}
+
+func normalizedServiceAccounts(entries []string) []string {
+	serviceAccounts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if sa, found := serviceAccount(entry); found {
+			entry = sa
+		}
+		serviceAccounts = append(serviceAccounts, entry)
+	}
+	return serviceAccounts
+}
+
+func (s *Settings) validateExemptions() error {
+	if _, err := CompilePatterns(s.ExemptUsers); err != nil {
+		return err
+	}
+	if _, err := CompilePatterns(s.ExemptGroups); err != nil {
+		return err
+	}
+
+	for _, entry := range normalizedServiceAccounts(s.ExemptServiceAccounts) {
+		namespace, name, found := strings.Cut(entry, ":")
+		if !found || namespace == "" || name == "" || strings.Contains(name, ":") {
+			return InvalidServiceAccountError{ServiceAccount: entry}
+		}
+		if _, err := CompilePattern(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exemption returns the identity of the user exempting the request from the
+// validation, checking the username, the groups and the service account.
+// Identities are case sensitive, the literal entries must match exactly.
+func (s *Settings) Exemption(userInfo kubewarden_protocol.UserInfo) (Exemption, bool) {
+	if s.exemptPatterns == nil {
+		s.exemptPatterns = map[ExemptionKind]Patterns{
+			ExemptionUser:           compileValidPatterns(s.ExemptUsers),
+			ExemptionGroup:          compileValidPatterns(s.ExemptGroups),
+			ExemptionServiceAccount: compileValidPatterns(normalizedServiceAccounts(s.ExemptServiceAccounts)),
+		}
+	}
+
+	sa, isServiceAccount := serviceAccount(userInfo.Username)
+	if isServiceAccount && s.exemptPatterns[ExemptionServiceAccount].MatchAnyExactly(sa) {
+		return Exemption{Kind: ExemptionServiceAccount, Identity: userInfo.Username}, true
+	}
+	if userInfo.Username != "" && s.exemptPatterns[ExemptionUser].MatchAnyExactly(userInfo.Username) {
+		return Exemption{Kind: ExemptionUser, Identity: userInfo.Username}, true
+	}
+	for _, group := range userInfo.Groups {
+		if s.exemptPatterns[ExemptionGroup].MatchAnyExactly(group) {
+			return Exemption{Kind: ExemptionGroup, Identity: group}, true
+		}
+	}
+	return Exemption{}, false
+}
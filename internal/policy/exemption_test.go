@@ -0,0 +1,176 @@
+package policy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/francoispqt/onelog"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExemption(t *testing.T) {
+	settings := policy.Settings{
+		ExemptUsers:           []string{"admin", "ci-*"},
+		ExemptGroups:          []string{"break-glass"},
+		ExemptServiceAccounts: []string{"flux-system:*", "system:serviceaccount:argocd:argocd-application-controller"},
+	}
+
+	type testCase struct {
+		name              string
+		userInfo          kubewarden_protocol.UserInfo
+		expectedExempted  bool
+		expectedExemption policy.Exemption
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:              "exempt username",
+			userInfo:          kubewarden_protocol.UserInfo{Username: "admin"},
+			expectedExempted:  true,
+			expectedExemption: policy.Exemption{Kind: policy.ExemptionUser, Identity: "admin"},
+		},
+		{
+			name:              "username matching a glob",
+			userInfo:          kubewarden_protocol.UserInfo{Username: "ci-runner"},
+			expectedExempted:  true,
+			expectedExemption: policy.Exemption{Kind: policy.ExemptionUser, Identity: "ci-runner"},
+		},
+		{
+			name: "exempt group",
+			userInfo: kubewarden_protocol.UserInfo{
+				Username: "alice",
+				Groups:   []string{"system:authenticated", "break-glass"},
+			},
+			expectedExempted:  true,
+			expectedExemption: policy.Exemption{Kind: policy.ExemptionGroup, Identity: "break-glass"},
+		},
+		{
+			name:             "service account matching a glob",
+			userInfo:         kubewarden_protocol.UserInfo{Username: "system:serviceaccount:flux-system:kustomize-controller"},
+			expectedExempted: true,
+			expectedExemption: policy.Exemption{
+				Kind:     policy.ExemptionServiceAccount,
+				Identity: "system:serviceaccount:flux-system:kustomize-controller",
+			},
+		},
+		{
+			name:             "service account configured with its username",
+			userInfo:         kubewarden_protocol.UserInfo{Username: "system:serviceaccount:argocd:argocd-application-controller"}, //nolint:lll
+			expectedExempted: true,
+			expectedExemption: policy.Exemption{
+				Kind:     policy.ExemptionServiceAccount,
+				Identity: "system:serviceaccount:argocd:argocd-application-controller",
+			},
+		},
+		{
+			name:             "service account of another namespace",
+			userInfo:         kubewarden_protocol.UserInfo{Username: "system:serviceaccount:default:kustomize-controller"},
+			expectedExempted: false,
+		},
+		{
+			name:             "username with a different case",
+			userInfo:         kubewarden_protocol.UserInfo{Username: "ADMIN"},
+			expectedExempted: false,
+		},
+		{
+			name:             "fullwidth username",
+			userInfo:         kubewarden_protocol.UserInfo{Username: "\uff41\uff44\uff4d\uff49\uff4e"},
+			expectedExempted: false,
+		},
+		{
+			name: "group with a different case",
+			userInfo: kubewarden_protocol.UserInfo{
+				Username: "alice",
+				Groups:   []string{"Break-Glass"},
+			},
+			expectedExempted: false,
+		},
+		{
+			name:             "service account with a different case",
+			userInfo:         kubewarden_protocol.UserInfo{Username: "system:serviceaccount:ArgoCD:argocd-application-controller"}, //nolint:lll
+			expectedExempted: false,
+		},
+		{
+			name: "not exempt user",
+			userInfo: kubewarden_protocol.UserInfo{
+				Username: "bob",
+				Groups:   []string{"system:authenticated"},
+			},
+			expectedExempted: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exemption, exempted := settings.Exemption(tc.userInfo)
+			assert.Equal(t, tc.expectedExempted, exempted)
+			assert.Equal(t, tc.expectedExemption, exemption)
+		})
+	}
+}
+
+func TestExemptionsValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		settings      policy.Settings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "valid exemptions",
+			settings: policy.Settings{
+				ExemptUsers:           []string{"admin"},
+				ExemptGroups:          []string{"break-glass", "regex:ops-.+"},
+				ExemptServiceAccounts: []string{"flux-system:*", "system:serviceaccount:argocd:controller"},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "service account without namespace",
+			settings: policy.Settings{
+				ExemptServiceAccounts: []string{"controller"},
+			},
+			expectedError: policy.InvalidServiceAccountError{ServiceAccount: "controller"},
+		},
+		{
+			name: "invalid group pattern",
+			settings: policy.Settings{
+				ExemptGroups: []string{"ops-[a"},
+			},
+			expectedError: policy.InvalidPatternError{Pattern: "ops-[a", Reason: "syntax error in pattern"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings.Validate()
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestValidateExemptedRequest(t *testing.T) {
+	validate := policy.NewValidate(&onelog.Logger{})
+	settingsRaw, err := json.Marshal(policy.Settings{ExemptGroups: []string{"break-glass"}})
+	require.NoError(t, err)
+
+	for groups, expectedAccepted := range map[string]bool{
+		"break-glass": true,
+		"developers":  false,
+	} {
+		payload, err := json.Marshal(kubewarden_protocol.ValidationRequest{
+			Request: kubewarden_protocol.KubernetesAdmissionRequest{
+				UserInfo: kubewarden_protocol.UserInfo{Username: "alice", Groups: []string{groups}},
+				Object:   []byte(`{"metadata": {"name": "legacy", "labels": {"level": "1"}}}`),
+			},
+			Settings: settingsRaw,
+		})
+		require.NoError(t, err)
+
+		var response kubewarden_protocol.ValidationResponse
+		result, err := validate(payload)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(result, &response))
+		assert.Equal(t, expectedAccepted, response.Accepted, groups)
+	}
+}
@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LabelKeyScope selects the parts of a label key checked by the policy.
+type LabelKeyScope string
+
+const (
+	// LabelKeyScopeKey checks the whole key, prefix included.
+	LabelKeyScopeKey LabelKeyScope = "key"
+	// LabelKeyScopeName checks only the name of the key.
+	LabelKeyScopeName LabelKeyScope = "name"
+	// LabelKeyScopePrefix checks only the DNS prefix of the key.
+	LabelKeyScopePrefix LabelKeyScope = "prefix"
+	// LabelKeyScopePrefixLabels checks each DNS label of the prefix.
+	LabelKeyScopePrefixLabels LabelKeyScope = "prefix_labels"
+)
+
+type InvalidLabelKeyScopeError struct {
+	Scope LabelKeyScope
+}
+
+func (e InvalidLabelKeyScopeError) Error() string {
+	return fmt.Sprintf(
+		"%s is not a valid label key scope, it must be one of %s, %s, %s, %s",
+		e.Scope,
+		LabelKeyScopeKey,
+		LabelKeyScopeName,
+		LabelKeyScopePrefix,
+		LabelKeyScopePrefixLabels,
+	)
+}
+
+func (s LabelKeyScope) Validate() error {
+	switch s {
+	case "", LabelKeyScopeKey, LabelKeyScopeName, LabelKeyScopePrefix, LabelKeyScopePrefixLabels:
+		return nil
+	default:
+		return InvalidLabelKeyScopeError{Scope: s}
+	}
+}
+
+// maxLabelNameLength is the longest name of a label key, after its prefix.
+const maxLabelNameLength = 63
+
+// maxLabelKeyLength is the longest label key, a DNS subdomain prefix of at
+// most 253 characters, a slash and a name.
+const maxLabelKeyLength = 253 + 1 + maxLabelNameLength
+
+// isValidLabelName reports whether the name follows the Kubernetes syntax of
+// the label key names: alphanumeric characters, dashes, underscores and
+// dots, starting and ending with an alphanumeric character.
+func isValidLabelName(name string) bool {
+	if name == "" || len(name) > maxLabelNameLength {
+		return false
+	}
+	for i := range len(name) {
+		c := name[i]
+		alphanumeric := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		if (i == 0 || i == len(name)-1) && !alphanumeric {
+			return false
+		}
+		if !alphanumeric && c != '-' && c != '_' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// LabelKey is a label key split following the Kubernetes syntax:
+// an optional DNS subdomain prefix, followed by a slash, and a name.
+type LabelKey struct {
+	Prefix string
+	Name   string
+}
+
+func ParseLabelKey(key string) LabelKey {
+	prefix, name, found := strings.Cut(key, "/")
+	if !found {
+		return LabelKey{Name: key}
+	}
+	return LabelKey{Prefix: prefix, Name: name}
+}
+
+// Parts returns the non empty parts of the key in the given scope.
+func (k LabelKey) Parts(scope LabelKeyScope) []string {
+	if scope == "" {
+		scope = LabelKeyScopeKey
+	}
+
+	var parts []string
+	switch scope {
+	case LabelKeyScopeKey:
+		parts = []string{k.String()}
+	case LabelKeyScopeName:
+		parts = []string{k.Name}
+	case LabelKeyScopePrefix:
+		parts = []string{k.Prefix}
+	case LabelKeyScopePrefixLabels:
+		parts = strings.Split(k.Prefix, ".")
+	}
+
+	nonEmptyParts := parts[:0]
+	for _, part := range parts {
+		if part != "" {
+			nonEmptyParts = append(nonEmptyParts, part)
+		}
+	}
+	return nonEmptyParts
+}
+
+func (k LabelKey) String() string {
+	if k.Prefix == "" {
+		return k.Name
+	}
+	return k.Prefix + "/" + k.Name
+}
+
+// IsForbiddenLabelKey reports whether the detectors of the labels refuse
+// the label key, like ValidateLabels does.
+func (s *Settings) IsForbiddenLabelKey(labelKey string) bool {
+	return s.isForbidden(labelKey, SourceLabel)
+}
@@ -0,0 +1,126 @@
+package policy_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelKey(t *testing.T) {
+	type testCase struct {
+		key              string
+		expectedLabelKey policy.LabelKey
+	}
+
+	for _, tc := range []testCase{
+		{
+			key:              "level",
+			expectedLabelKey: policy.LabelKey{Name: "level"},
+		},
+		{
+			key:              "app.kubernetes.io/name",
+			expectedLabelKey: policy.LabelKey{Prefix: "app.kubernetes.io", Name: "name"},
+		},
+		{
+			key:              "aba.example.com/x",
+			expectedLabelKey: policy.LabelKey{Prefix: "aba.example.com", Name: "x"},
+		},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			labelKey := policy.ParseLabelKey(tc.key)
+			assert.Equal(t, tc.expectedLabelKey, labelKey)
+			assert.Equal(t, tc.key, labelKey.String())
+		})
+	}
+}
+
+func TestLabelKeyParts(t *testing.T) {
+	labelKey := policy.ParseLabelKey("aba.example.com/level")
+
+	assert.Equal(t, []string{"aba.example.com/level"}, labelKey.Parts(policy.LabelKeyScopeKey))
+	assert.Equal(t, []string{"level"}, labelKey.Parts(policy.LabelKeyScopeName))
+	assert.Equal(t, []string{"aba.example.com"}, labelKey.Parts(policy.LabelKeyScopePrefix))
+	assert.Equal(t, []string{"aba", "example", "com"}, labelKey.Parts(policy.LabelKeyScopePrefixLabels))
+	assert.Empty(t, policy.ParseLabelKey("level").Parts(policy.LabelKeyScopePrefix))
+}
+
+func TestIsForbiddenLabelKey(t *testing.T) {
+	type testCase struct {
+		key               string
+		scope             policy.LabelKeyScope
+		allowed           []string
+		expectedForbidden bool
+	}
+
+	for _, tc := range []testCase{
+		{key: "level", scope: "", expectedForbidden: true},
+		{key: "level.example.com/foo", scope: "", expectedForbidden: false},
+		{key: "level", scope: policy.LabelKeyScopeName, expectedForbidden: true},
+		{key: "example.com/level", scope: policy.LabelKeyScopeName, expectedForbidden: true},
+		{key: "level.example.com/foo", scope: policy.LabelKeyScopeName, expectedForbidden: false},
+		{key: "level", scope: policy.LabelKeyScopePrefix, expectedForbidden: false},
+		{key: "aba/foo", scope: policy.LabelKeyScopePrefix, expectedForbidden: true},
+		{key: "level.example.com/foo", scope: policy.LabelKeyScopePrefix, expectedForbidden: false},
+		{key: "level.example.com/foo", scope: policy.LabelKeyScopePrefixLabels, expectedForbidden: true},
+		{key: "app.kubernetes.io/name", scope: policy.LabelKeyScopePrefixLabels, expectedForbidden: false},
+		{
+			key:               "level.example.com/foo",
+			scope:             policy.LabelKeyScopePrefixLabels,
+			allowed:           []string{"level"},
+			expectedForbidden: false,
+		},
+		{
+			key:               "example.com/level",
+			scope:             policy.LabelKeyScopeName,
+			allowed:           []string{"level"},
+			expectedForbidden: false,
+		},
+	} {
+		t.Run(fmt.Sprintf("%s with scope %q", tc.key, tc.scope), func(t *testing.T) {
+			settings := policy.Settings{
+				AllowedPalindromes: tc.allowed,
+				LabelKeyScope:      tc.scope,
+			}
+			assert.Equal(t, tc.expectedForbidden, settings.IsForbiddenLabelKey(tc.key))
+		})
+	}
+}
+
+func TestIsForbiddenLabelKeyFollowsTheDetectors(t *testing.T) {
+	type testCase struct {
+		name      string
+		settings  policy.Settings
+		key       string
+		forbidden bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:      "denied label key",
+			settings:  policy.Settings{DeniedLabelKeys: []string{"team"}},
+			key:       "team",
+			forbidden: true,
+		},
+		{
+			name:      "palindromic substring",
+			settings:  policy.Settings{PalindromicSubstringLength: 4},
+			key:       "xnoonx-a",
+			forbidden: true,
+		},
+		{
+			name:      "labels listed with no detectors",
+			settings:  policy.Settings{Detectors: map[policy.Source][]string{policy.SourceLabel: {}}},
+			key:       "level",
+			forbidden: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			require.NoError(t, settings.Validate())
+			assert.Equal(t, tc.forbidden, settings.IsForbiddenLabelKey(tc.key))
+		})
+	}
+}
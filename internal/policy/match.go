@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+)
+
+// defaultSeparators are the separators of the label keys.
+const defaultSeparators = "-_./"
+
+// MatchMode is the way the words are compared to find the palindromes.
+type MatchMode string
+
+const (
+	// MatchStrict compares every character of the word, separators included.
+	MatchStrict MatchMode = "strict"
+	// MatchIgnoreSeparators removes the separators before comparing.
+	MatchIgnoreSeparators MatchMode = "ignore_separators"
+	// MatchSegment flags the word when one of its separator delimited
+	// segments is a palindrome.
+	MatchSegment MatchMode = "segment"
+)
+
+type InvalidMatchModeError struct {
+	Mode MatchMode
+}
+
+func (e InvalidMatchModeError) Error() string {
+	return fmt.Sprintf(
+		"%s is not a valid match mode, it must be one of %s, %s, %s",
+		e.Mode,
+		MatchStrict,
+		MatchIgnoreSeparators,
+		MatchSegment,
+	)
+}
+
+type InvalidMinLengthError struct {
+	MinLength int
+}
+
+func (e InvalidMinLengthError) Error() string {
+	return fmt.Sprintf("%d is not a valid minimum length, it cannot be negative", e.MinLength)
+}
+
+func (m MatchMode) Validate() error {
+	switch m {
+	case "", MatchStrict, MatchIgnoreSeparators, MatchSegment:
+		return nil
+	default:
+		return InvalidMatchModeError{Mode: m}
+	}
+}
+
+func (s *Settings) separators() string {
+	if s.Separators == "" {
+		return defaultSeparators
+	}
+	return s.Separators
+}
+
+func (s *Settings) validateMinLength() error {
+	if s.MinLength < 0 {
+		return InvalidMinLengthError{MinLength: s.MinLength}
+	}
+	return nil
+}
+
+// candidates returns the words compared by the match mode: the word itself
+// or, in segment mode, each of its segments. They are replaced by their
+// skeleton when the confusable characters are compared by their prototype.
+func (s *Settings) candidates(w string) []string {
+	if s.ConfusableSkeleton {
+		w = word.Skeleton(w)
+	}
+	if s.MatchMode == MatchSegment {
+		return word.Segments(w, s.separators())
+	}
+	return []string{w}
+}
+
+// compared returns the form of the candidate compared by the match mode.
+func (s *Settings) compared(candidate string) string {
+	if s.MatchMode == MatchIgnoreSeparators {
+		return word.StripSeparators(candidate, s.separators())
+	}
+	return candidate
+}
@@ -0,0 +1,364 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchModes(t *testing.T) {
+	type testCase struct {
+		name      string
+		settings  policy.Settings
+		labelKey  string
+		forbidden bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:      "strict mode compares the separators too",
+			settings:  policy.Settings{MatchMode: policy.MatchStrict},
+			labelKey:  "a-b.a",
+			forbidden: false,
+		},
+		{
+			name:      "strict mode is the default",
+			settings:  policy.Settings{},
+			labelKey:  "top_spot",
+			forbidden: false,
+		},
+		{
+			name:      "strict mode still rejects palindromes with a separator in the middle",
+			settings:  policy.Settings{},
+			labelKey:  "aba-aba",
+			forbidden: true,
+		},
+		{
+			name:      "ignore separators mode removes the separators",
+			settings:  policy.Settings{MatchMode: policy.MatchIgnoreSeparators},
+			labelKey:  "a-b.a",
+			forbidden: true,
+		},
+		{
+			name:      "ignore separators mode removes the underscores",
+			settings:  policy.Settings{MatchMode: policy.MatchIgnoreSeparators},
+			labelKey:  "top_spot",
+			forbidden: true,
+		},
+		{
+			name:      "ignore separators mode accepts words that are not palindromes",
+			settings:  policy.Settings{MatchMode: policy.MatchIgnoreSeparators},
+			labelKey:  "team-level",
+			forbidden: false,
+		},
+		{
+			name:      "ignore separators mode with custom separators",
+			settings:  policy.Settings{MatchMode: policy.MatchIgnoreSeparators, Separators: "-"},
+			labelKey:  "top_spot",
+			forbidden: false,
+		},
+		{
+			name:      "segment mode rejects a palindrome segment",
+			settings:  policy.Settings{MatchMode: policy.MatchSegment},
+			labelKey:  "team-level-x",
+			forbidden: true,
+		},
+		{
+			name:      "segment mode rejects single character segments",
+			settings:  policy.Settings{MatchMode: policy.MatchSegment},
+			labelKey:  "a-b",
+			forbidden: true,
+		},
+		{
+			name:      "segment mode accepts words without palindrome segments",
+			settings:  policy.Settings{MatchMode: policy.MatchSegment},
+			labelKey:  "team-web",
+			forbidden: false,
+		},
+		{
+			name:      "segment mode with custom separators",
+			settings:  policy.Settings{MatchMode: policy.MatchSegment, Separators: ":"},
+			labelKey:  "team-level-x",
+			forbidden: false,
+		},
+		{
+			name: "segment mode accepts the allowed segments",
+			settings: policy.Settings{
+				MatchMode:          policy.MatchSegment,
+				AllowedPalindromes: []string{"level"},
+			},
+			labelKey:  "team-level-ab",
+			forbidden: false,
+		},
+		{
+			name: "segment mode accepts the allowed whole words",
+			settings: policy.Settings{
+				MatchMode:          policy.MatchSegment,
+				AllowedPalindromes: []string{"team-*"},
+			},
+			labelKey:  "team-level",
+			forbidden: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			assert.Equal(t, tc.forbidden, settings.IsForbiddenLabelKey(tc.labelKey))
+		})
+	}
+}
+
+func TestMinLength(t *testing.T) {
+	type testCase struct {
+		name      string
+		settings  policy.Settings
+		labelKey  string
+		forbidden bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:      "single characters are palindromes by default",
+			settings:  policy.Settings{},
+			labelKey:  "x",
+			forbidden: true,
+		},
+		{
+			name:      "palindrome shorter than the minimum length",
+			settings:  policy.Settings{MinLength: 3},
+			labelKey:  "ii",
+			forbidden: false,
+		},
+		{
+			name:      "palindrome as long as the minimum length",
+			settings:  policy.Settings{MinLength: 3},
+			labelKey:  "aba",
+			forbidden: true,
+		},
+		{
+			name:      "length counted after the normalization",
+			settings:  policy.Settings{MinLength: 3},
+			labelKey:  "e\u0301e\u0301",
+			forbidden: false,
+		},
+		{
+			name:      "length counted after the case folding",
+			settings:  policy.Settings{MinLength: 3},
+			labelKey:  "ßs",
+			forbidden: true,
+		},
+		{
+			name:      "length counted after removing the separators",
+			settings:  policy.Settings{MatchMode: policy.MatchIgnoreSeparators, MinLength: 3},
+			labelKey:  "a-a",
+			forbidden: false,
+		},
+		{
+			name:      "short segments are not palindromes",
+			settings:  policy.Settings{MatchMode: policy.MatchSegment, MinLength: 3},
+			labelKey:  "team-a",
+			forbidden: false,
+		},
+		{
+			name:      "long segments are still palindromes",
+			settings:  policy.Settings{MatchMode: policy.MatchSegment, MinLength: 3},
+			labelKey:  "x-level",
+			forbidden: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			assert.Equal(t, tc.forbidden, settings.IsForbiddenLabelKey(tc.labelKey))
+		})
+	}
+}
+
+func TestMinLengthAppliesToValues(t *testing.T) {
+	settings := policy.Settings{PalindromeValueKeys: []string{"tier"}, MinLength: 2}
+
+	assert.False(t, settings.IsForbiddenValue("tier", "a"))
+	assert.True(t, settings.IsForbiddenValue("tier", "aa"))
+}
+
+func TestMatchModesApplyToNames(t *testing.T) {
+	settings := policy.Settings{MatchMode: policy.MatchIgnoreSeparators, Names: &policy.NameSettings{}}
+
+	assert.True(t, settings.IsForbiddenName("top-spot"))
+	assert.False(t, settings.IsForbiddenName("top-spots"))
+}
+
+func TestMatchModeSettingsValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		settings      policy.Settings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:          "known match mode",
+			settings:      policy.Settings{MatchMode: policy.MatchSegment},
+			expectedError: nil,
+		},
+		{
+			name:          "unknown match mode",
+			settings:      policy.Settings{MatchMode: "fuzzy"},
+			expectedError: policy.InvalidMatchModeError{Mode: "fuzzy"},
+		},
+		{
+			name:          "allowed palindrome with separators in strict mode",
+			settings:      policy.Settings{AllowedPalindromes: []string{"a-b.a"}},
+			expectedError: policy.AllowedPalindromeError{Field: "a-b.a"},
+		},
+		{
+			name: "allowed palindrome with separators in ignore separators mode",
+			settings: policy.Settings{
+				MatchMode:          policy.MatchIgnoreSeparators,
+				AllowedPalindromes: []string{"a-b.a"},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "allowed palindrome with a palindrome segment in segment mode",
+			settings: policy.Settings{
+				MatchMode:          policy.MatchSegment,
+				AllowedPalindromes: []string{"team-level"},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "allowed name palindrome checked with the match mode",
+			settings: policy.Settings{
+				MatchMode: policy.MatchIgnoreSeparators,
+				Names:     &policy.NameSettings{AllowedPalindromes: []string{"top-spots"}},
+			},
+			expectedError: policy.AllowedPalindromeError{Field: "top-spots"},
+		},
+		{
+			name:          "negative minimum length",
+			settings:      policy.Settings{MinLength: -1},
+			expectedError: policy.InvalidMinLengthError{MinLength: -1},
+		},
+		{
+			name:          "allowed palindrome shorter than the minimum length",
+			settings:      policy.Settings{MinLength: 3, AllowedPalindromes: []string{"ii"}},
+			expectedError: policy.ShortAllowedPalindromeError{Field: "ii", MinLength: 3},
+		},
+		{
+			name:          "allowed palindrome as long as the minimum length",
+			settings:      policy.Settings{MinLength: 3, AllowedPalindromes: []string{"aba"}},
+			expectedError: nil,
+		},
+		{
+			name:          "allowed pattern not checked against the minimum length",
+			settings:      policy.Settings{MinLength: 3, AllowedPalindromes: []string{"a*"}},
+			expectedError: nil,
+		},
+		{
+			name: "allowed palindrome shorter than the minimum length once the separators are removed",
+			settings: policy.Settings{
+				MatchMode:          policy.MatchIgnoreSeparators,
+				MinLength:          3,
+				AllowedPalindromes: []string{"a-a"},
+			},
+			expectedError: policy.ShortAllowedPalindromeError{Field: "a-a", MinLength: 3},
+		},
+		{
+			name: "allowed namespace palindrome shorter than the minimum length",
+			settings: policy.Settings{
+				MinLength:                   3,
+				NamespaceAllowedPalindromes: map[string][]string{"team-a": {"aa"}},
+			},
+			expectedError: policy.ShortAllowedPalindromeError{Field: "aa", MinLength: 3},
+		},
+		{
+			name: "allowed annotation palindrome shorter than the minimum length",
+			settings: policy.Settings{
+				MinLength:   2,
+				Annotations: &policy.AnnotationSettings{AllowedPalindromes: []string{"x"}},
+			},
+			expectedError: policy.ShortAllowedPalindromeError{Field: "x", MinLength: 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			err := settings.Validate()
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestConfusableSkeleton(t *testing.T) {
+	type testCase struct {
+		name      string
+		settings  policy.Settings
+		labelKey  string
+		forbidden bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:      "confusable characters are distinct by default",
+			settings:  policy.Settings{},
+			labelKey:  "l\u0435vel",
+			forbidden: false,
+		},
+		{
+			name:      "invisible characters are kept by default",
+			settings:  policy.Settings{},
+			labelKey:  "le\u200bvel",
+			forbidden: false,
+		},
+		{
+			name:      "cyrillic letter compared by its prototype",
+			settings:  policy.Settings{ConfusableSkeleton: true},
+			labelKey:  "l\u0435vel",
+			forbidden: true,
+		},
+		{
+			name:      "uppercase i compared by its prototype",
+			settings:  policy.Settings{ConfusableSkeleton: true},
+			labelKey:  "leveI",
+			forbidden: true,
+		},
+		{
+			name:      "uppercase i kept without the skeleton",
+			settings:  policy.Settings{},
+			labelKey:  "leveI",
+			forbidden: false,
+		},
+		{
+			name:      "zero width characters removed",
+			settings:  policy.Settings{ConfusableSkeleton: true},
+			labelKey:  "le\u200bv\u200del",
+			forbidden: true,
+		},
+		{
+			name:      "skeleton of a word that is not a palindrome",
+			settings:  policy.Settings{ConfusableSkeleton: true},
+			labelKey:  "n\u0435ver",
+			forbidden: false,
+		},
+		{
+			name: "skeleton matching an allowed palindrome",
+			settings: policy.Settings{
+				ConfusableSkeleton: true,
+				AllowedPalindromes: []string{"level"},
+			},
+			labelKey:  "l\u0435vel",
+			forbidden: false,
+		},
+		{
+			name:      "skeleton with the separators ignored",
+			settings:  policy.Settings{ConfusableSkeleton: true, MatchMode: policy.MatchIgnoreSeparators},
+			labelKey:  "aba\u2010ab\u0430",
+			forbidden: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			assert.Equal(t, tc.forbidden, settings.IsForbiddenLabelKey(tc.labelKey))
+		})
+	}
+}
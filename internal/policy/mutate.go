@@ -0,0 +1,312 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/tidwall/gjson"
+)
+
+// defaultMutationAnnotationKey is the annotation holding the labels moved
+// by the annotate strategy, when not configured.
+const defaultMutationAnnotationKey = "palindrome-policy.kubewarden.io/removed-labels"
+
+// MutationStrategy is the way the policy fixes the forbidden label keys
+// instead of rejecting the request.
+type MutationStrategy string
+
+const (
+	// MutationDrop removes the forbidden labels.
+	MutationDrop MutationStrategy = "drop"
+	// MutationRename renames the forbidden labels adding a prefix and a suffix.
+	MutationRename MutationStrategy = "rename"
+	// MutationAnnotate moves the forbidden labels in an annotation, keeping
+	// their original key and value.
+	MutationAnnotate MutationStrategy = "annotate"
+)
+
+type MutationSettings struct {
+	Strategy      MutationStrategy `json:"strategy"`
+	Prefix        string           `json:"prefix,omitempty"`
+	Suffix        string           `json:"suffix,omitempty"`
+	AnnotationKey string           `json:"annotation_key,omitempty"`
+}
+
+type InvalidMutationError struct {
+	Reason string
+}
+
+func (e InvalidMutationError) Error() string {
+	return fmt.Sprintf("mutation settings not valid: %s", e.Reason)
+}
+
+// MutationError is returned when the forbidden keys cannot be fixed.
+type MutationError struct {
+	Key    string
+	Path   string
+	Source Source
+	Reason string
+}
+
+func (e MutationError) Error() string {
+	return fmt.Sprintf("%s at %s cannot be mutated: %s", subject(e.Source, e.Key), e.Path, e.Reason)
+}
+
+func (m *MutationSettings) Validate() error {
+	switch m.Strategy {
+	case MutationDrop, MutationAnnotate:
+		return nil
+	case MutationRename:
+		if m.Prefix == "" && m.Suffix == "" {
+			return InvalidMutationError{Reason: "the rename strategy requires a prefix or a suffix"}
+		}
+		if strings.Contains(m.Prefix+m.Suffix, "/") {
+			return InvalidMutationError{Reason: "prefix and suffix cannot contain a slash"}
+		}
+		// the shortest renamed name must be valid
+		if !isValidLabelName(m.Prefix + "x" + m.Suffix) {
+			return InvalidMutationError{
+				Reason: fmt.Sprintf(
+					"prefix %q and suffix %q do not make valid label names, they can hold alphanumeric "+
+						"characters, '-', '_' and '.', the prefix must start and the suffix must end with an "+
+						"alphanumeric character, and together they cannot be longer than %d characters",
+					m.Prefix,
+					m.Suffix,
+					maxLabelNameLength-1,
+				),
+			}
+		}
+		return nil
+	default:
+		return InvalidMutationError{
+			Reason: fmt.Sprintf(
+				"%s is not a valid strategy, it must be one of %s, %s, %s",
+				m.Strategy,
+				MutationDrop,
+				MutationRename,
+				MutationAnnotate,
+			),
+		}
+	}
+}
+
+func (m *MutationSettings) annotationKey() string {
+	if m.AnnotationKey == "" {
+		return defaultMutationAnnotationKey
+	}
+	return m.AnnotationKey
+}
+
+// MutateLabels returns the admitted object with the labels of the violations
+// fixed following the mutation strategy of the settings. Only labels can be
+// fixed, the violations of other sources are returned as errors, and so are
+// the palindrome values with the rename strategy, that keeps them.
+func MutateLabels(
+	request *kubewarden_protocol.KubernetesAdmissionRequest,
+	violations []Violation,
+	settings *Settings,
+) (map[string]interface{}, error) {
+	for _, v := range violations {
+		if v.Source != SourceLabel {
+			return nil, MutationError{Key: v.Key, Path: v.Path, Source: v.Source, Reason: "only labels can be mutated"}
+		}
+		if settings.Mutation.Strategy != MutationRename {
+			continue
+		}
+		switch v.Rule {
+		case RulePalindromeValue:
+			return nil, MutationError{
+				Key:    v.Key,
+				Path:   v.Path,
+				Source: v.Source,
+				Reason: fmt.Sprintf("renaming the key does not fix the palindrome value %s", v.Value),
+			}
+		case RulePalindromicSubstring:
+			return nil, MutationError{
+				Key:    v.Key,
+				Path:   v.Path,
+				Source: v.Source,
+				Reason: fmt.Sprintf("renaming the key does not remove the palindromic substring %q", v.Value),
+			}
+		case RuleBidiControl:
+			return nil, MutationError{
+				Key:    v.Key,
+				Path:   v.Path,
+				Source: v.Source,
+				Reason: "renaming the key does not remove the bidirectional control characters",
+			}
+		case RulePalindrome, RuleDeniedLabelKey, RuleNearPalindrome, RulePermutationPalindrome:
+			// the renamed key is checked again
+		case RuleReversePair, RuleReverseValue:
+			// the renamed key is no longer the reverse of the other member
+		}
+	}
+	settings = settings.ForNamespace(request.Namespace)
+
+	var object map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(request.Object))
+	// keep the numbers as they are, instead of converting them to float64
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("could not decode the object to mutate: %w", err)
+	}
+
+	selectorPath, selectorKeys := workloadSelectorKeys(request.Kind.Kind, gjson.ParseBytes(request.Object))
+	for _, path := range labelsPaths(request.Kind.Kind) {
+		var keys []string
+		for _, v := range violations {
+			if v.Path == jsonPath(path, v.Key) {
+				keys = append(keys, v.Key)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if isPodTemplateLabelsPath(request.Kind.Kind, path) {
+			if err := selectedKeysError(path, keys, selectorPath, selectorKeys); err != nil {
+				return nil, err
+			}
+		}
+
+		metadataPath := strings.TrimSuffix(path, ".labels")
+		metadata, found := lookupMap(object, metadataPath)
+		if !found {
+			continue
+		}
+		if err := mutateMetadata(metadata, path, uniqueSorted(keys), settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return object, nil
+}
+
+func isPodTemplateLabelsPath(kind, path string) bool {
+	templatePath, found := podTemplatePath(kind)
+	return found && path == templatePath+".metadata.labels"
+}
+
+// selectedKeysError refuses to mutate the pod template labels used by the
+// workload selector: the pod template would no longer match it, and the
+// selector cannot be changed on update.
+func selectedKeysError(path string, keys []string, selectorPath string, selectorKeys map[string]bool) error {
+	for _, key := range keys {
+		if selectorKeys[key] {
+			return MutationError{
+				Key:    key,
+				Path:   jsonPath(path, key),
+				Source: SourceLabel,
+				Reason: fmt.Sprintf("the key is used by the workload selector %s", selectorPath),
+			}
+		}
+	}
+	return nil
+}
+
+func mutateMetadata(metadata map[string]interface{}, path string, keys []string, settings *Settings) error {
+	labels, _ := metadata["labels"].(map[string]interface{})
+	mutation := settings.Mutation
+
+	switch mutation.Strategy {
+	case MutationDrop:
+		for _, key := range keys {
+			delete(labels, key)
+		}
+	case MutationRename:
+		for _, key := range keys {
+			renamed := renameLabelKey(key, mutation.Prefix, mutation.Suffix)
+			if !isValidLabelName(ParseLabelKey(renamed).Name) {
+				return MutationError{
+					Key:    key,
+					Path:   jsonPath(path, key),
+					Source: SourceLabel,
+					Reason: fmt.Sprintf("renamed key %s is not a valid label key", renamed),
+				}
+			}
+			if _, found := labels[renamed]; found {
+				return MutationError{
+					Key:    key,
+					Path:   jsonPath(path, key),
+					Source: SourceLabel,
+					Reason: fmt.Sprintf("label %s already exists", renamed),
+				}
+			}
+			if len(settings.keyViolations(renamed, jsonPath(path, renamed), SourceLabel)) > 0 {
+				return MutationError{
+					Key:    key,
+					Path:   jsonPath(path, key),
+					Source: SourceLabel,
+					Reason: fmt.Sprintf("renamed key %s is still forbidden", renamed),
+				}
+			}
+			labels[renamed] = labels[key]
+			delete(labels, key)
+		}
+	case MutationAnnotate:
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		if annotations == nil {
+			annotations = map[string]interface{}{}
+			metadata["annotations"] = annotations
+		}
+		annotationKey := mutation.annotationKey()
+
+		moved := map[string]interface{}{}
+		if existing, found := annotations[annotationKey].(string); found && existing != "" {
+			if err := json.Unmarshal([]byte(existing), &moved); err != nil {
+				return MutationError{
+					Key:    keys[0],
+					Path:   jsonPath(path, keys[0]),
+					Source: SourceLabel,
+					Reason: fmt.Sprintf("annotation %s does not hold a JSON object", annotationKey),
+				}
+			}
+		}
+		for _, key := range keys {
+			moved[key] = labels[key]
+			delete(labels, key)
+		}
+		movedRaw, err := json.Marshal(moved)
+		if err != nil {
+			return err
+		}
+		annotations[annotationKey] = string(movedRaw)
+	}
+
+	return nil
+}
+
+// renameLabelKey adds the prefix and the suffix to the name of the key,
+// keeping its DNS prefix.
+func renameLabelKey(key, prefix, suffix string) string {
+	labelKey := ParseLabelKey(key)
+	labelKey.Name = prefix + labelKey.Name + suffix
+	return labelKey.String()
+}
+
+// lookupMap returns the map found following the dot separated path.
+func lookupMap(object map[string]interface{}, path string) (map[string]interface{}, bool) {
+	current := object
+	for _, segment := range strings.Split(path, ".") {
+		next, found := current[segment].(map[string]interface{})
+		if !found {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+	unique := values[:0]
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,368 @@
+package policy_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/francoispqt/onelog"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validateRawObject(
+	t *testing.T,
+	kind string,
+	object string,
+	settings policy.Settings,
+) kubewarden_protocol.ValidationResponse {
+	t.Helper()
+
+	validate := policy.NewValidate(&onelog.Logger{})
+	settingsRaw, err := json.Marshal(settings)
+	require.NoError(t, err)
+	payload, err := json.Marshal(kubewarden_protocol.ValidationRequest{
+		Request: kubewarden_protocol.KubernetesAdmissionRequest{
+			Kind:   kubewarden_protocol.GroupVersionKind{Kind: kind},
+			Object: []byte(object),
+		},
+		Settings: settingsRaw,
+	})
+	require.NoError(t, err)
+
+	var response kubewarden_protocol.ValidationResponse
+	result, err := validate(payload)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(result, &response))
+	return response
+}
+
+func TestMutateLabels(t *testing.T) {
+	pod := `{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {
+			"name": "test-pod",
+			"labels": {"level": "debug", "team": "a", "example.com/aba": "x"}
+		},
+		"spec": {"containers": [{"name": "app", "image": "nginx"}]}
+	}`
+	deployment := `{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {"name": "web", "labels": {"level": "debug"}},
+		"spec": {
+			"replicas": 3,
+			"selector": {"matchLabels": {"app": "web"}},
+			"template": {
+				"metadata": {
+					"labels": {"aba": "x", "app": "web"},
+					"annotations": {"owner": "team-a"}
+				},
+				"spec": {"containers": [{"name": "app", "image": "nginx"}]}
+			}
+		}
+	}`
+
+	type testCase struct {
+		name           string
+		kind           string
+		object         string
+		mutation       policy.MutationSettings
+		expectedObject string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "drop strategy removes the palindrome labels of a pod",
+			kind:     "Pod",
+			object:   pod,
+			mutation: policy.MutationSettings{Strategy: policy.MutationDrop},
+			expectedObject: `{
+				"apiVersion": "v1",
+				"kind": "Pod",
+				"metadata": {"name": "test-pod", "labels": {"team": "a"}},
+				"spec": {"containers": [{"name": "app", "image": "nginx"}]}
+			}`,
+		},
+		{
+			name:     "rename strategy adds the prefix and the suffix to the name of the palindrome labels",
+			kind:     "Pod",
+			object:   pod,
+			mutation: policy.MutationSettings{Strategy: policy.MutationRename, Prefix: "legacy-", Suffix: "-key"},
+			expectedObject: `{
+				"apiVersion": "v1",
+				"kind": "Pod",
+				"metadata": {
+					"name": "test-pod",
+					"labels": {"legacy-level-key": "debug", "team": "a", "example.com/legacy-aba-key": "x"}
+				},
+				"spec": {"containers": [{"name": "app", "image": "nginx"}]}
+			}`,
+		},
+		{
+			name:     "annotate strategy moves the palindrome labels in the default annotation",
+			kind:     "Pod",
+			object:   pod,
+			mutation: policy.MutationSettings{Strategy: policy.MutationAnnotate},
+			expectedObject: `{
+				"apiVersion": "v1",
+				"kind": "Pod",
+				"metadata": {
+					"name": "test-pod",
+					"labels": {"team": "a"},
+					"annotations": {
+						"palindrome-policy.kubewarden.io/removed-labels": "{\"example.com/aba\":\"x\",\"level\":\"debug\"}"
+					}
+				},
+				"spec": {"containers": [{"name": "app", "image": "nginx"}]}
+			}`,
+		},
+		{
+			name:     "drop strategy removes the palindrome labels of a deployment and of its pod template",
+			kind:     "Deployment",
+			object:   deployment,
+			mutation: policy.MutationSettings{Strategy: policy.MutationDrop},
+			expectedObject: `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"metadata": {"name": "web", "labels": {}},
+				"spec": {
+					"replicas": 3,
+					"selector": {"matchLabels": {"app": "web"}},
+					"template": {
+						"metadata": {"labels": {"app": "web"}, "annotations": {"owner": "team-a"}},
+						"spec": {"containers": [{"name": "app", "image": "nginx"}]}
+					}
+				}
+			}`,
+		},
+		{
+			name:     "annotate strategy moves the pod template labels in a pod template annotation",
+			kind:     "Deployment",
+			object:   deployment,
+			mutation: policy.MutationSettings{Strategy: policy.MutationAnnotate, AnnotationKey: "example.com/moved"},
+			expectedObject: `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"metadata": {
+					"name": "web",
+					"labels": {},
+					"annotations": {"example.com/moved": "{\"level\":\"debug\"}"}
+				},
+				"spec": {
+					"replicas": 3,
+					"selector": {"matchLabels": {"app": "web"}},
+					"template": {
+						"metadata": {
+							"labels": {"app": "web"},
+							"annotations": {"owner": "team-a", "example.com/moved": "{\"aba\":\"x\"}"}
+						},
+						"spec": {"containers": [{"name": "app", "image": "nginx"}]}
+					}
+				}
+			}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mutation := tc.mutation
+			response := validateRawObject(t, tc.kind, tc.object, policy.Settings{
+				LabelKeyScope: policy.LabelKeyScopeName,
+				Mutation:      &mutation,
+			})
+
+			require.True(t, response.Accepted)
+			var expectedObject interface{}
+			require.NoError(t, json.Unmarshal([]byte(tc.expectedObject), &expectedObject))
+			assert.Equal(t, expectedObject, response.MutatedObject)
+		})
+	}
+}
+
+func TestMutateLabelsErrors(t *testing.T) {
+	type testCase struct {
+		name                 string
+		kind                 string
+		object               string
+		mutation             policy.MutationSettings
+		expectedErrorContent string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:                 "renamed label already exists",
+			object:               `{"metadata": {"labels": {"level": "a", "level-old": "b"}}}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationRename, Suffix: "-old"},
+			expectedErrorContent: "label with key level at metadata.labels.level cannot be mutated: label level-old already exists", //nolint:lll
+		},
+		{
+			name:                 "renamed label with a prefix already exists",
+			object:               `{"metadata": {"labels": {"level": "a", "old-level": "b"}}}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationRename, Prefix: "old-"},
+			expectedErrorContent: "label with key level at metadata.labels.level cannot be mutated: label old-level already exists", //nolint:lll
+		},
+		{
+			name:                 "renamed label too long",
+			object:               `{"metadata": {"labels": {"` + strings.Repeat("a", 61) + `": "a"}}}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationRename, Suffix: "-old"},
+			expectedErrorContent: "renamed key " + strings.Repeat("a", 61) + "-old is not a valid label key",
+		},
+		{
+			name:                 "renamed label still a palindrome",
+			object:               `{"metadata": {"labels": {"aba": "a"}}}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationRename, Prefix: "x", Suffix: "x"},
+			expectedErrorContent: "label with key aba at metadata.labels.aba cannot be mutated: renamed key xabax is still forbidden", //nolint:lll
+		},
+		{
+			name: "annotation not holding a JSON object",
+			object: `{"metadata": {
+				"labels": {"aba": "a"},
+				"annotations": {"palindrome-policy.kubewarden.io/removed-labels": "nope"}
+			}}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationAnnotate},
+			expectedErrorContent: "annotation palindrome-policy.kubewarden.io/removed-labels does not hold a JSON object",
+		},
+		{
+			name: "pod template label used by the matchLabels of the workload selector",
+			kind: "Deployment",
+			object: `{
+				"metadata": {"name": "web"},
+				"spec": {
+					"selector": {"matchLabels": {"level": "a"}},
+					"template": {"metadata": {"labels": {"level": "a"}}}
+				}
+			}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationDrop},
+			expectedErrorContent: "label with key level at spec.template.metadata.labels.level cannot be mutated: the key is used by the workload selector spec.selector", //nolint:lll
+		},
+		{
+			name: "pod template label used by the matchExpressions of the workload selector",
+			kind: "StatefulSet",
+			object: `{
+				"metadata": {"name": "db"},
+				"spec": {
+					"selector": {"matchExpressions": [{"key": "aba", "operator": "Exists"}]},
+					"template": {"metadata": {"labels": {"aba": "a"}}}
+				}
+			}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationRename, Suffix: "-old"},
+			expectedErrorContent: "label with key aba at spec.template.metadata.labels.aba cannot be mutated: the key is used by the workload selector spec.selector", //nolint:lll
+		},
+		{
+			name: "pod template label used by the selector of a replication controller",
+			kind: "ReplicationController",
+			object: `{
+				"metadata": {"name": "legacy"},
+				"spec": {
+					"selector": {"radar": "on"},
+					"template": {"metadata": {"labels": {"radar": "on"}}}
+				}
+			}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationAnnotate},
+			expectedErrorContent: "label with key radar at spec.template.metadata.labels.radar cannot be mutated: the key is used by the workload selector spec.selector", //nolint:lll
+		},
+		{
+			name: "job template pod label used by the selector of a cronjob",
+			kind: "CronJob",
+			object: `{
+				"metadata": {"name": "backup"},
+				"spec": {"jobTemplate": {"spec": {
+					"selector": {"matchLabels": {"kayak": "a"}},
+					"template": {"metadata": {"labels": {"kayak": "a"}}}
+				}}}
+			}`,
+			mutation:             policy.MutationSettings{Strategy: policy.MutationDrop},
+			expectedErrorContent: "label with key kayak at spec.jobTemplate.spec.template.metadata.labels.kayak cannot be mutated: the key is used by the workload selector spec.jobTemplate.spec.selector", //nolint:lll
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kind := tc.kind
+			if kind == "" {
+				kind = "Pod"
+			}
+			mutation := tc.mutation
+			response := validateRawObject(t, kind, tc.object, policy.Settings{Mutation: &mutation})
+
+			assert.False(t, response.Accepted)
+			assert.Nil(t, response.MutatedObject)
+			assert.Contains(t, *response.Message, tc.expectedErrorContent)
+		})
+	}
+}
+
+func TestMutateAcceptsObjectsWithoutViolations(t *testing.T) {
+	response := validateRawObject(
+		t,
+		"Pod",
+		`{"metadata": {"labels": {"team": "a"}}}`,
+		policy.Settings{Mutation: &policy.MutationSettings{Strategy: policy.MutationDrop}},
+	)
+
+	assert.True(t, response.Accepted)
+	assert.Nil(t, response.MutatedObject)
+}
+
+func TestMutationSettingsValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		mutation      policy.MutationSettings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:          "drop strategy",
+			mutation:      policy.MutationSettings{Strategy: policy.MutationDrop},
+			expectedError: nil,
+		},
+		{
+			name:          "rename strategy with a suffix",
+			mutation:      policy.MutationSettings{Strategy: policy.MutationRename, Suffix: "-legacy"},
+			expectedError: nil,
+		},
+		{
+			name:     "rename strategy without prefix and suffix",
+			mutation: policy.MutationSettings{Strategy: policy.MutationRename},
+			expectedError: policy.InvalidMutationError{
+				Reason: "the rename strategy requires a prefix or a suffix",
+			},
+		},
+		{
+			name:     "rename strategy with a slash in the prefix",
+			mutation: policy.MutationSettings{Strategy: policy.MutationRename, Prefix: "example.com/"},
+			expectedError: policy.InvalidMutationError{
+				Reason: "prefix and suffix cannot contain a slash",
+			},
+		},
+		{
+			name:     "rename strategy with a suffix ending with a dash",
+			mutation: policy.MutationSettings{Strategy: policy.MutationRename, Suffix: "-"},
+			expectedError: policy.InvalidMutationError{
+				Reason: `prefix "" and suffix "-" do not make valid label names, they can hold alphanumeric characters, '-', '_' and '.', the prefix must start and the suffix must end with an alphanumeric character, and together they cannot be longer than 62 characters`, //nolint:lll
+			},
+		},
+		{
+			name:     "rename strategy with a prefix holding a space",
+			mutation: policy.MutationSettings{Strategy: policy.MutationRename, Prefix: "old "},
+			expectedError: policy.InvalidMutationError{
+				Reason: `prefix "old " and suffix "" do not make valid label names, they can hold alphanumeric characters, '-', '_' and '.', the prefix must start and the suffix must end with an alphanumeric character, and together they cannot be longer than 62 characters`, //nolint:lll
+			},
+		},
+		{
+			name:     "unknown strategy",
+			mutation: policy.MutationSettings{Strategy: "hide"},
+			expectedError: policy.InvalidMutationError{
+				Reason: "hide is not a valid strategy, it must be one of drop, rename, annotate",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mutation := tc.mutation
+			settings := policy.Settings{Mutation: &mutation}
+			err := settings.Validate()
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	namePath         = "metadata.name"
+	generateNamePath = "metadata.generateName"
+
+	// the API server appends a random suffix of these characters to the
+	// generateName prefix, truncated to keep the name within 63 characters
+	generatedSuffixLength       = 5
+	generatedSuffixCharacters   = "bcdfghjklmnpqrstvwxz2456789"
+	maxGenerateNamePrefixLength = 58
+)
+
+// NameSettings enables the validation of the object name and of its
+// generateName prefix, for any kind.
+type NameSettings struct {
+	// Palindrome names accepted by the policy, by exact name or pattern.
+	// The allowed palindromes of the labels do not apply to the names.
+	AllowedPalindromes []string `json:"allowed_palindromes,omitempty"`
+}
+
+func (n *NameSettings) Validate() error {
+	_, err := CompilePatterns(n.AllowedPalindromes)
+	return err
+}
+
+// IsForbiddenName reports whether the detectors of the names refuse the
+// name, like ValidateLabels does. Names are never forbidden when their check
+// is disabled.
+func (s *Settings) IsForbiddenName(name string) bool {
+	if s.Names == nil || name == "" {
+		return false
+	}
+	return s.isForbidden(name, SourceName)
+}
+
+func (s *Settings) isAnAllowedNamePalindrome(palindrome string) bool {
+	if s.allowedNamePalindromePatterns == nil {
+		s.allowedNamePalindromePatterns = compileValidPatterns(s.Names.AllowedPalindromes)
+	}
+	return s.allowedNamePalindromePatterns.MatchAny(palindrome)
+}
+
+// nameViolations checks the name and the generateName prefix of the object.
+// The names generated by the API server are skipped, their prefix is
+// validated, and so are the unchanged names of an update when only new
+// violations are reported.
+func (s *Settings) nameViolations(object, oldObject gjson.Result, grandfatherOldNames bool) []Violation {
+	var violations []Violation
+	for _, path := range []string{namePath, generateNamePath} {
+		name := object.Get(path).String()
+		if name == "" || isGeneratedName(object, path, name) {
+			continue
+		}
+		if grandfatherOldNames && oldObject.Get(path).String() == name {
+			continue
+		}
+		// generateName prefixes usually end with a dash separating the
+		// random suffix
+		violations = append(violations, s.detectorViolations(
+			strings.TrimRight(name, "-"),
+			Violation{Key: name, Path: path, Source: SourceName},
+		)...)
+	}
+	return violations
+}
+
+// isGeneratedName reports whether the name has been made by the API server,
+// appending its random suffix to the generateName prefix, that is validated
+// on its own. The owner references are not trusted: any client can set them.
+func isGeneratedName(object gjson.Result, path, name string) bool {
+	generateName := object.Get(generateNamePath).String()
+	if path != namePath || generateName == "" {
+		return false
+	}
+	if len(generateName) > maxGenerateNamePrefixLength {
+		generateName = generateName[:maxGenerateNamePrefixLength]
+	}
+	suffix, found := strings.CutPrefix(name, generateName)
+	return found && len(suffix) == generatedSuffixLength && strings.Trim(suffix, generatedSuffixCharacters) == ""
+}
@@ -0,0 +1,212 @@
+package policy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsForbiddenName(t *testing.T) {
+	type testCase struct {
+		name       string
+		settings   policy.Settings
+		objectName string
+		forbidden  bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:       "names not checked by default",
+			settings:   policy.Settings{},
+			objectName: "kayak",
+			forbidden:  false,
+		},
+		{
+			name:       "palindrome name",
+			settings:   policy.Settings{Names: &policy.NameSettings{}},
+			objectName: "kayak",
+			forbidden:  true,
+		},
+		{
+			name:       "not palindrome name",
+			settings:   policy.Settings{Names: &policy.NameSettings{}},
+			objectName: "nginx",
+			forbidden:  false,
+		},
+		{
+			name:       "palindrome name allowed by the names allowlist",
+			settings:   policy.Settings{Names: &policy.NameSettings{AllowedPalindromes: []string{"regex:k.*k"}}},
+			objectName: "kayak",
+			forbidden:  false,
+		},
+		{
+			name: "allowed palindromes of the labels do not apply to the names",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"kayak"},
+				Names:              &policy.NameSettings{},
+			},
+			objectName: "kayak",
+			forbidden:  true,
+		},
+		{
+			name: "name checked by the listed detectors",
+			settings: policy.Settings{
+				Names:     &policy.NameSettings{},
+				Detectors: map[policy.Source][]string{policy.SourceName: {"segment"}},
+			},
+			objectName: "web-kayak",
+			forbidden:  true,
+		},
+		{
+			name: "name of a field listed with no detectors",
+			settings: policy.Settings{
+				Names:     &policy.NameSettings{},
+				Detectors: map[policy.Source][]string{policy.SourceName: {}},
+			},
+			objectName: "kayak",
+			forbidden:  false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			assert.Equal(t, tc.forbidden, settings.IsForbiddenName(tc.objectName))
+		})
+	}
+}
+
+func TestValidateNames(t *testing.T) {
+	type testCase struct {
+		name               string
+		kind               string
+		operation          string
+		object             string
+		oldObject          string
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:   "palindrome pod name",
+			kind:   "Pod",
+			object: `{"metadata": {"name": "kayak"}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "kayak", Path: "metadata.name", Source: policy.SourceName, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:   "palindrome namespace name",
+			kind:   "Namespace",
+			object: `{"metadata": {"name": "level"}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "level", Path: "metadata.name", Source: policy.SourceName, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:   "palindrome service name",
+			kind:   "Service",
+			object: `{"metadata": {"name": "radar"}, "spec": {"ports": [{"port": 80}]}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "radar", Path: "metadata.name", Source: policy.SourceName, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:   "palindrome generateName prefix",
+			kind:   "Pod",
+			object: `{"metadata": {"generateName": "kayak-"}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "kayak-", Path: "metadata.generateName", Source: policy.SourceName, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:   "name made by the generateName prefix and a random suffix",
+			kind:   "Pod",
+			object: `{"metadata": {"name": "gfdcb-bcdfg", "generateName": "gfdcb-"}}`,
+		},
+		{
+			name:   "name starting with the generateName prefix without a random suffix",
+			kind:   "Pod",
+			object: `{"metadata": {"name": "abc-bcb-cba", "generateName": "abc-"}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "abc-bcb-cba", Path: "metadata.name", Source: policy.SourceName, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name: "name made by a long generateName prefix truncated by the API server",
+			kind: "Pod",
+			object: `{"metadata": {
+				"name": "bcdfg` + strings.Repeat("a", 53) + `gfdcb",
+				"generateName": "bcdfg` + strings.Repeat("a", 53) + `xx"
+			}}`,
+		},
+		{
+			name: "name starting with the name of a controller owner",
+			kind: "ReplicaSet",
+			object: `{"metadata": {
+				"name": "web-bew",
+				"ownerReferences": [{"kind": "Deployment", "name": "web", "controller": true}]
+			}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "web-bew", Path: "metadata.name", Source: policy.SourceName, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name: "palindrome generateName prefix of a pod owned by a controller",
+			kind: "Pod",
+			object: `{"metadata": {
+				"name": "web-bew-x7k2q",
+				"generateName": "web-bew-",
+				"ownerReferences": [{"kind": "ReplicaSet", "name": "web-bew", "controller": true}]
+			}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "web-bew-", Path: "metadata.generateName", Source: policy.SourceName, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:      "unchanged name of an update with new violations only",
+			kind:      "Pod",
+			operation: "UPDATE",
+			object:    `{"metadata": {"name": "kayak", "labels": {"team": "a"}}}`,
+			oldObject: `{"metadata": {"name": "kayak"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:      kubewarden_protocol.GroupVersionKind{Kind: tc.kind},
+				Operation: tc.operation,
+				Object:    []byte(tc.object),
+				OldObject: []byte(tc.oldObject),
+			}
+			settings := policy.Settings{Names: &policy.NameSettings{}, NewViolationsOnly: true}
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestNameViolationMessage(t *testing.T) {
+	err := policy.ViolationsError{
+		Violations: []policy.Violation{
+			{Key: "kayak", Path: "metadata.name", Source: policy.SourceName, Rule: policy.RulePalindrome},
+		},
+	}
+
+	assert.Equal(t, "name kayak at metadata.name not allowed, the word is a palindrome", err.Error())
+}
+
+func TestNameSettingsValidation(t *testing.T) {
+	settings := policy.Settings{Names: &policy.NameSettings{AllowedPalindromes: []string{"nginx"}}}
+
+	require.ErrorIs(t, settings.Validate(), policy.AllowedPalindromeError{Field: "nginx"})
+}
@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+)
+
+type OverlappingNamespacesError struct {
+	Setting string
+	First   string
+	Second  string
+}
+
+func (e OverlappingNamespacesError) Error() string {
+	return fmt.Sprintf(
+		"%s entries %s and %s overlap, a namespace must match only one of them",
+		e.Setting,
+		e.First,
+		e.Second,
+	)
+}
+
+type ExcludedNamespaceAllowlistError struct {
+	Namespace string
+	Excluded  string
+}
+
+func (e ExcludedNamespaceAllowlistError) Error() string {
+	return fmt.Sprintf(
+		"namespace_allowed_palindromes entry %s is excluded by %s, its allowed palindromes would never be used",
+		e.Namespace,
+		e.Excluded,
+	)
+}
+
+// validateNamespaces checks the namespaces patterns, refusing overlapping
+// entries and allowed palindromes of excluded namespaces.
+func (s *Settings) validateNamespaces() error {
+	excluded, err := CompilePatterns(s.ExcludedNamespaces)
+	if err != nil {
+		return err
+	}
+	if err := validateNotOverlapping("excluded_namespaces", excluded); err != nil {
+		return err
+	}
+
+	namespaces, err := CompilePatterns(s.namespacesWithAllowedPalindromes())
+	if err != nil {
+		return err
+	}
+	if err := validateNotOverlapping("namespace_allowed_palindromes", namespaces); err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		for _, excludedNamespace := range excluded {
+			if namespace.Overlaps(excludedNamespace) {
+				return ExcludedNamespaceAllowlistError{
+					Namespace: namespace.String(),
+					Excluded:  excludedNamespace.String(),
+				}
+			}
+		}
+		if err := s.validateAllowedPalindromes(
+			s.NamespaceAllowedPalindromes[namespace.String()],
+			labelAllowlistSources()...,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateNotOverlapping(setting string, patterns Patterns) error {
+	for i := range patterns {
+		for j := i + 1; j < len(patterns); j++ {
+			if patterns[i].Overlaps(patterns[j]) {
+				return OverlappingNamespacesError{
+					Setting: setting,
+					First:   patterns[i].String(),
+					Second:  patterns[j].String(),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// namespacesWithAllowedPalindromes returns the sorted namespace entries of
+// the namespace allowed palindromes, to evaluate them in a stable order.
+func (s *Settings) namespacesWithAllowedPalindromes() []string {
+	namespaces := make([]string, 0, len(s.NamespaceAllowedPalindromes))
+	for namespace := range s.NamespaceAllowedPalindromes {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// IsAnExcludedNamespace reports whether the policy skips the namespace.
+func (s *Settings) IsAnExcludedNamespace(namespace string) bool {
+	if s.excludedNamespacePatterns == nil {
+		s.excludedNamespacePatterns = compileValidPatterns(s.ExcludedNamespaces)
+	}
+	return s.excludedNamespacePatterns.MatchAny(namespace)
+}
+
+// ForNamespace returns the settings to apply in the namespace, the global
+// allowed palindromes are extended with the ones of the matching namespace
+// entries.
+func (s *Settings) ForNamespace(namespace string) *Settings {
+	var namespaceAllowedPalindromes []string
+	for _, entry := range s.namespacesWithAllowedPalindromes() {
+		pattern, err := CompilePattern(entry)
+		if err == nil && pattern.Match(namespace) {
+			namespaceAllowedPalindromes = append(namespaceAllowedPalindromes, s.NamespaceAllowedPalindromes[entry]...)
+		}
+	}
+	if len(namespaceAllowedPalindromes) == 0 {
+		return s
+	}
+
+	namespaceSettings := *s
+	namespaceSettings.AllowedPalindromes = make([]string, 0, len(s.AllowedPalindromes)+len(namespaceAllowedPalindromes))
+	namespaceSettings.AllowedPalindromes = append(namespaceSettings.AllowedPalindromes, s.AllowedPalindromes...)
+	namespaceSettings.AllowedPalindromes = append(namespaceSettings.AllowedPalindromes, namespaceAllowedPalindromes...)
+	namespaceSettings.allowedPalindromePatterns = nil
+	return &namespaceSettings
+}
@@ -0,0 +1,180 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAnExcludedNamespace(t *testing.T) {
+	settings := policy.Settings{
+		ExcludedNamespaces: []string{"kube-system", "kubewarden-*"},
+	}
+
+	assert.True(t, settings.IsAnExcludedNamespace("kube-system"))
+	assert.True(t, settings.IsAnExcludedNamespace("kubewarden-system"))
+	assert.False(t, settings.IsAnExcludedNamespace("default"))
+	assert.False(t, settings.IsAnExcludedNamespace(""))
+}
+
+func TestForNamespace(t *testing.T) {
+	settings := policy.Settings{
+		AllowedPalindromes: []string{"level"},
+		NamespaceAllowedPalindromes: map[string][]string{
+			"team-a":   {"aba"},
+			"tenant-*": {"radar"},
+		},
+	}
+
+	teamSettings := settings.ForNamespace("team-a")
+	assert.True(t, teamSettings.IsAnAllowedPalindrome("level"))
+	assert.True(t, teamSettings.IsAnAllowedPalindrome("aba"))
+	assert.False(t, teamSettings.IsAnAllowedPalindrome("radar"))
+
+	tenantSettings := settings.ForNamespace("tenant-1")
+	assert.True(t, tenantSettings.IsAnAllowedPalindrome("radar"))
+	assert.False(t, tenantSettings.IsAnAllowedPalindrome("aba"))
+
+	defaultSettings := settings.ForNamespace("default")
+	assert.True(t, defaultSettings.IsAnAllowedPalindrome("level"))
+	assert.False(t, defaultSettings.IsAnAllowedPalindrome("aba"))
+	assert.Equal(t, []string{"level"}, settings.AllowedPalindromes)
+}
+
+func TestValidateLabelsInNamespaces(t *testing.T) {
+	settings := policy.Settings{
+		ExcludedNamespaces: []string{"kube-system"},
+		NamespaceAllowedPalindromes: map[string][]string{
+			"team-a": {"level"},
+		},
+	}
+
+	for namespace, expectedViolation := range map[string]bool{
+		"kube-system": false,
+		"team-a":      false,
+		"team-b":      true,
+	} {
+		t.Run(namespace, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Namespace: namespace,
+				Object:    []byte(`{"metadata": {"labels": {"level": "1"}}}`),
+			}
+			err := policy.ValidateLabels(&request, &settings)
+			assert.Equal(t, expectedViolation, err != nil)
+		})
+	}
+}
+
+func TestNamespacesValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		settings      policy.Settings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "consistent namespace entries",
+			settings: policy.Settings{
+				ExcludedNamespaces: []string{"kube-system", "kubewarden"},
+				NamespaceAllowedPalindromes: map[string][]string{
+					"team-a":   {"aba"},
+					"tenant-*": {"team-*-maet"},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "overlapping excluded namespaces",
+			settings: policy.Settings{
+				ExcludedNamespaces: []string{"kube-*", "kube-system"},
+			},
+			expectedError: policy.OverlappingNamespacesError{
+				Setting: "excluded_namespaces",
+				First:   "kube-*",
+				Second:  "kube-system",
+			},
+		},
+		{
+			name: "overlapping namespace allowed palindromes",
+			settings: policy.Settings{
+				NamespaceAllowedPalindromes: map[string][]string{
+					"team-a": {"aba"},
+					"team-*": {"level"},
+				},
+			},
+			expectedError: policy.OverlappingNamespacesError{
+				Setting: "namespace_allowed_palindromes",
+				First:   "team-*",
+				Second:  "team-a",
+			},
+		},
+		{
+			name: "allowed palindromes of an excluded namespace",
+			settings: policy.Settings{
+				ExcludedNamespaces: []string{"kube-*"},
+				NamespaceAllowedPalindromes: map[string][]string{
+					"kube-system": {"aba"},
+				},
+			},
+			expectedError: policy.ExcludedNamespaceAllowlistError{
+				Namespace: "kube-system",
+				Excluded:  "kube-*",
+			},
+		},
+		{
+			name: "allowed palindromes of namespaces matched by an excluded glob",
+			settings: policy.Settings{
+				ExcludedNamespaces: []string{"kube-*"},
+				NamespaceAllowedPalindromes: map[string][]string{
+					"*-system": {"aba"},
+				},
+			},
+			expectedError: policy.ExcludedNamespaceAllowlistError{
+				Namespace: "*-system",
+				Excluded:  "kube-*",
+			},
+		},
+		{
+			name: "overlapping namespace globs and regexes",
+			settings: policy.Settings{
+				NamespaceAllowedPalindromes: map[string][]string{
+					"regex:team-[a-z]+": {"aba"},
+					"team-*":            {"level"},
+				},
+			},
+			expectedError: policy.OverlappingNamespacesError{
+				Setting: "namespace_allowed_palindromes",
+				First:   "regex:team-[a-z]+",
+				Second:  "team-*",
+			},
+		},
+		{
+			name: "namespace allowed palindrome not a palindrome",
+			settings: policy.Settings{
+				NamespaceAllowedPalindromes: map[string][]string{
+					"team-a": {"rancher"},
+				},
+			},
+			expectedError: policy.AllowedPalindromeError{Field: "rancher"},
+		},
+		{
+			name: "namespace allowed palindrome conflicting with a denied label key",
+			settings: policy.Settings{
+				DeniedLabelKeys: []string{"level"},
+				NamespaceAllowedPalindromes: map[string][]string{
+					"team-a": {"level"},
+				},
+			},
+			expectedError: policy.ConflictingLabelKeyError{Allowed: "level", Denied: "level"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings.Validate()
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
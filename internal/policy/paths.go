@@ -0,0 +1,258 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+// PathMode is what is checked in the fields selected by a path rule.
+type PathMode string
+
+const (
+	// PathModeKeys checks the keys of the maps.
+	PathModeKeys PathMode = "keys"
+	// PathModeValues checks the string values of the maps and of the lists,
+	// or the selected string itself.
+	PathModeValues PathMode = "values"
+	// PathModeBoth checks both the keys and the string values.
+	PathModeBoth PathMode = "both"
+)
+
+// PathRule selects with a gjson path the fields to check in the objects of
+// the given kinds, or of every kind when no kind is set.
+type PathRule struct {
+	Path  string   `json:"path"`
+	Mode  PathMode `json:"mode,omitempty"`
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+type InvalidPathError struct {
+	Path   string
+	Reason string
+}
+
+func (e InvalidPathError) Error() string {
+	return fmt.Sprintf("%s is not a valid path: %s", e.Path, e.Reason)
+}
+
+type InvalidPathModeError struct {
+	Path string
+	Mode PathMode
+}
+
+func (e InvalidPathModeError) Error() string {
+	return fmt.Sprintf(
+		"%s is not a valid mode of the path %s, it must be one of %s, %s, %s",
+		e.Mode,
+		e.Path,
+		PathModeKeys,
+		PathModeValues,
+		PathModeBoth,
+	)
+}
+
+func (r *PathRule) Validate() error {
+	if err := validateGJSONPath(r.Path); err != nil {
+		return err
+	}
+	switch r.mode() {
+	case PathModeKeys, PathModeValues, PathModeBoth:
+		return nil
+	default:
+		return InvalidPathModeError{Path: r.Path, Mode: r.Mode}
+	}
+}
+
+// mode returns the mode of the rule, the keys are checked by default.
+func (r *PathRule) mode() PathMode {
+	if r.Mode == "" {
+		return PathModeKeys
+	}
+	return r.Mode
+}
+
+func (r *PathRule) appliesTo(kind string) bool {
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, k := range r.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Settings) validatePaths() error {
+	for i := range s.Paths {
+		if err := s.Paths[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateGJSONPath checks the syntax of the path: the components cannot be
+// empty, the escapes must be followed by a character and the strings and the
+// brackets of the queries, of the multipaths and of the modifier arguments
+// must be closed.
+func validateGJSONPath(path string) error {
+	if path == "" {
+		return InvalidPathError{Path: path, Reason: "the path cannot be empty"}
+	}
+
+	var closers []byte
+	componentLength := 0
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\':
+			if i == len(path)-1 {
+				return InvalidPathError{Path: path, Reason: "escape character at the end of the path"}
+			}
+			i++
+		case c == '"' && len(closers) > 0:
+			end := closingQuote(path, i)
+			if end < 0 {
+				return InvalidPathError{Path: path, Reason: "string not terminated"}
+			}
+			i = end
+		case c == '(' || c == '[' || c == '{':
+			closers = append(closers, closingBracket(c))
+		case c == ')' || c == ']' || c == '}':
+			if len(closers) == 0 || closers[len(closers)-1] != c {
+				return InvalidPathError{Path: path, Reason: fmt.Sprintf("unexpected %c", c)}
+			}
+			closers = closers[:len(closers)-1]
+		case (c == '.' || c == '|') && len(closers) == 0:
+			if componentLength == 0 {
+				return InvalidPathError{Path: path, Reason: "empty path component"}
+			}
+			componentLength = 0
+			continue
+		}
+		componentLength++
+	}
+
+	if len(closers) > 0 {
+		return InvalidPathError{Path: path, Reason: fmt.Sprintf("missing %c", closers[len(closers)-1])}
+	}
+	if componentLength == 0 {
+		return InvalidPathError{Path: path, Reason: "empty path component"}
+	}
+	return nil
+}
+
+func closingBracket(opening byte) byte {
+	switch opening {
+	case '(':
+		return ')'
+	case '[':
+		return ']'
+	default:
+		return '}'
+	}
+}
+
+// closingQuote returns the index of the quote closing the string starting
+// at the given index, or -1 when the string is not terminated.
+func closingQuote(path string, start int) int {
+	for i := start + 1; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// pathScanner collects the violations of the fields selected by the path
+// rules. The old object is walked together with the object, so that the
+// fields selected by queries can be grandfathered too.
+type pathScanner struct {
+	settings           *Settings
+	grandfatherOldKeys bool
+	violations         []Violation
+}
+
+// pathViolations checks the fields selected by the path rules applying to
+// the kind, skipping the keys and the unchanged values already present in
+// the old object of an update when only new violations are reported.
+func (s *Settings) pathViolations(
+	kind string,
+	object gjson.Result,
+	oldObject gjson.Result,
+	grandfatherOldKeys bool,
+) []Violation {
+	scanner := pathScanner{settings: s, grandfatherOldKeys: grandfatherOldKeys}
+	for i := range s.Paths {
+		rule := &s.Paths[i]
+		if rule.appliesTo(kind) {
+			scanner.scan(object.Get(rule.Path), oldObject.Get(rule.Path), rule.Path, rule.mode())
+		}
+	}
+	return scanner.violations
+}
+
+func (p *pathScanner) scan(field, oldField gjson.Result, path string, mode PathMode) {
+	switch {
+	case field.IsObject():
+		p.scanObject(field, oldField, path, mode)
+	case field.IsArray():
+		p.scanArray(field, oldField, path, mode)
+	case mode == PathModeValues || mode == PathModeBoth:
+		p.checkValue(field, oldField, path)
+	}
+}
+
+func (p *pathScanner) scanObject(field, oldField gjson.Result, path string, mode PathMode) {
+	checkKeys := mode == PathModeKeys || mode == PathModeBoth
+	checkValues := mode == PathModeValues || mode == PathModeBoth
+
+	field.ForEach(func(key, value gjson.Result) bool {
+		keyPath := jsonPath(path, key.String())
+		oldValue := oldField.Get(escapeGJSONKey(key.String()))
+		grandfatheredKey := p.grandfatherOldKeys && oldValue.Exists()
+		if checkKeys && !grandfatheredKey {
+			p.checkKey(key.String(), keyPath)
+		}
+		if checkValues {
+			p.checkValue(value, oldValue, keyPath)
+		}
+		return true
+	})
+}
+
+// scanArray checks the items of the list, the nested maps and lists are
+// scanned with the same mode.
+func (p *pathScanner) scanArray(field, oldField gjson.Result, path string, mode PathMode) {
+	oldItems := oldField.Array()
+	for i, item := range field.Array() {
+		var oldItem gjson.Result
+		if i < len(oldItems) {
+			oldItem = oldItems[i]
+		}
+		p.scan(item, oldItem, fmt.Sprintf("%s[%d]", path, i), mode)
+	}
+}
+
+func (p *pathScanner) checkKey(key, path string) {
+	p.violations = append(p.violations, p.settings.keyViolations(key, path, SourcePathKey)...)
+}
+
+// checkValue checks the string values, the other types are skipped.
+func (p *pathScanner) checkValue(value, oldValue gjson.Result, path string) {
+	if value.Type != gjson.String || value.Str == "" {
+		return
+	}
+	if p.grandfatherOldKeys && oldValue.Type == gjson.String && oldValue.Str == value.Str {
+		return
+	}
+	p.violations = append(p.violations, p.settings.detectorViolations(
+		value.Str,
+		Violation{Key: value.Str, Path: path, Source: SourcePathValue},
+	)...)
+}
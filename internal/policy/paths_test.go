@@ -0,0 +1,246 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const customResource = `{
+	"apiVersion": "example.com/v1",
+	"kind": "Widget",
+	"metadata": {"name": "widget"},
+	"spec": {
+		"selector": {"matchLabels": {"level": "noon", "app": "web"}},
+		"template": {"metadata": {"labels": {"radar": "x"}}},
+		"aliases": ["stats", "widget", 12321],
+		"owner": "anna",
+		"targets": [{"name": "civic"}, {"name": "main"}]
+	}
+}`
+
+func TestValidatePaths(t *testing.T) {
+	type testCase struct {
+		name               string
+		kind               string
+		settings           policy.Settings
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "keys mode",
+			kind: "Widget",
+			settings: policy.Settings{Paths: []policy.PathRule{
+				{Path: "spec.selector.matchLabels", Mode: policy.PathModeKeys},
+				{Path: "spec.template.metadata.labels"},
+			}},
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "level",
+					Path:   "spec.selector.matchLabels.level",
+					Source: policy.SourcePathKey,
+					Rule:   policy.RulePalindrome,
+				},
+				{
+					Key:    "radar",
+					Path:   "spec.template.metadata.labels.radar",
+					Source: policy.SourcePathKey,
+					Rule:   policy.RulePalindrome,
+				},
+			},
+		},
+		{
+			name: "values mode",
+			kind: "Widget",
+			settings: policy.Settings{Paths: []policy.PathRule{
+				{Path: "spec.selector.matchLabels", Mode: policy.PathModeValues},
+				{Path: "spec.aliases", Mode: policy.PathModeValues},
+				{Path: "spec.owner", Mode: policy.PathModeValues},
+				{Path: "spec.targets.#.name", Mode: policy.PathModeValues},
+			}},
+			expectedViolations: []policy.Violation{
+				{Key: "anna", Path: "spec.owner", Source: policy.SourcePathValue, Rule: policy.RulePalindrome},
+				{
+					Key:    "civic",
+					Path:   "spec.targets.#.name[0]",
+					Source: policy.SourcePathValue,
+					Rule:   policy.RulePalindrome,
+				},
+				{
+					Key:    "noon",
+					Path:   "spec.selector.matchLabels.level",
+					Source: policy.SourcePathValue,
+					Rule:   policy.RulePalindrome,
+				},
+				{Key: "stats", Path: "spec.aliases[0]", Source: policy.SourcePathValue, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name: "both mode with the allowed and denied keys",
+			kind: "Widget",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"noon"},
+				DeniedLabelKeys:    []string{"app"},
+				Paths: []policy.PathRule{
+					{Path: "spec.selector.matchLabels", Mode: policy.PathModeBoth},
+				},
+			},
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "app",
+					Path:   "spec.selector.matchLabels.app",
+					Source: policy.SourcePathKey,
+					Rule:   policy.RuleDeniedLabelKey,
+				},
+				{
+					Key:    "level",
+					Path:   "spec.selector.matchLabels.level",
+					Source: policy.SourcePathKey,
+					Rule:   policy.RulePalindrome,
+				},
+			},
+		},
+		{
+			name: "rule filtered by kind",
+			kind: "Widget",
+			settings: policy.Settings{Paths: []policy.PathRule{
+				{Path: "spec.selector.matchLabels", Kinds: []string{"Gadget"}},
+				{Path: "spec.template.metadata.labels", Kinds: []string{"Gadget", "Widget"}},
+			}},
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "radar",
+					Path:   "spec.template.metadata.labels.radar",
+					Source: policy.SourcePathKey,
+					Rule:   policy.RulePalindrome,
+				},
+			},
+		},
+		{
+			name: "missing path",
+			kind: "Widget",
+			settings: policy.Settings{Paths: []policy.PathRule{
+				{Path: "spec.missing", Mode: policy.PathModeBoth},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: tc.kind},
+				Object: []byte(customResource),
+			}
+			settings := tc.settings
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestValidatePathsGrandfathersOldFields(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:      kubewarden_protocol.GroupVersionKind{Kind: "Widget"},
+		Operation: "UPDATE",
+		Object:    []byte(`{"spec": {"labels": {"level": "noon", "radar": "ab"}, "aliases": ["stats", "kayak"]}}`),
+		OldObject: []byte(`{"spec": {"labels": {"level": "noon"}, "aliases": ["stats"]}}`),
+	}
+	settings := policy.Settings{
+		NewViolationsOnly: true,
+		Paths: []policy.PathRule{
+			{Path: "spec.labels", Mode: policy.PathModeBoth},
+			{Path: "spec.aliases", Mode: policy.PathModeValues},
+		},
+	}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{Key: "kayak", Path: "spec.aliases[1]", Source: policy.SourcePathValue, Rule: policy.RulePalindrome},
+		{Key: "radar", Path: "spec.labels.radar", Source: policy.SourcePathKey, Rule: policy.RulePalindrome},
+	}, violationsErr.Violations)
+	assert.Equal(
+		t,
+		"value kayak at spec.aliases[1] not allowed, the word is a palindrome; "+
+			"key radar at spec.labels.radar not allowed, the word is a palindrome",
+		violationsErr.Error(),
+	)
+}
+
+func TestPathRulesValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		rule          policy.PathRule
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:          "simple path",
+			rule:          policy.PathRule{Path: "spec.selector.matchLabels", Mode: policy.PathModeKeys},
+			expectedError: nil,
+		},
+		{
+			name:          "path with escapes, queries and modifiers",
+			rule:          policy.PathRule{Path: `spec.containers.#(name=="a.b\"c").env|@reverse`},
+			expectedError: nil,
+		},
+		{
+			name:          "empty path",
+			rule:          policy.PathRule{Path: ""},
+			expectedError: policy.InvalidPathError{Path: "", Reason: "the path cannot be empty"},
+		},
+		{
+			name:          "empty component",
+			rule:          policy.PathRule{Path: "spec..selector"},
+			expectedError: policy.InvalidPathError{Path: "spec..selector", Reason: "empty path component"},
+		},
+		{
+			name:          "trailing dot",
+			rule:          policy.PathRule{Path: "spec.selector."},
+			expectedError: policy.InvalidPathError{Path: "spec.selector.", Reason: "empty path component"},
+		},
+		{
+			name:          "dangling escape",
+			rule:          policy.PathRule{Path: `spec.selector\`},
+			expectedError: policy.InvalidPathError{Path: `spec.selector\`, Reason: "escape character at the end of the path"},
+		},
+		{
+			name:          "query not closed",
+			rule:          policy.PathRule{Path: `spec.containers.#(name=="a"`},
+			expectedError: policy.InvalidPathError{Path: `spec.containers.#(name=="a"`, Reason: "missing )"},
+		},
+		{
+			name:          "string not terminated",
+			rule:          policy.PathRule{Path: `spec.containers.#(name=="a)`},
+			expectedError: policy.InvalidPathError{Path: `spec.containers.#(name=="a)`, Reason: "string not terminated"},
+		},
+		{
+			name:          "mismatched brackets",
+			rule:          policy.PathRule{Path: "spec.[a,b)"},
+			expectedError: policy.InvalidPathError{Path: "spec.[a,b)", Reason: "unexpected )"},
+		},
+		{
+			name:          "unknown mode",
+			rule:          policy.PathRule{Path: "spec.selector", Mode: "entries"},
+			expectedError: policy.InvalidPathModeError{Path: "spec.selector", Mode: "entries"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := policy.Settings{Paths: []policy.PathRule{tc.rule}}
+			require.ErrorIs(t, settings.Validate(), tc.expectedError)
+		})
+	}
+}
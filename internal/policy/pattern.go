@@ -0,0 +1,315 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+)
+
+const (
+	// regexPatternPrefix marks an entry as a RE2 regular expression.
+	regexPatternPrefix = "regex:"
+	// globMetaCharacters cannot be part of a label key, an entry holding
+	// one of them is a glob.
+	globMetaCharacters = "*?["
+
+	maxPatternLength     = 256
+	maxGlobWildcards     = 8
+	maxRegexRepeatCount  = 100
+	maxRegexProgramInsts = 2000
+)
+
+type patternKind int
+
+const (
+	literalPattern patternKind = iota
+	globPattern
+	regexPattern
+)
+
+type InvalidPatternError struct {
+	Pattern string
+	Reason  string
+}
+
+func (e InvalidPatternError) Error() string {
+	return fmt.Sprintf("%s is not a valid pattern: %s", e.Pattern, e.Reason)
+}
+
+// Pattern is an entry of a settings list, it can be a literal, a glob
+// like team-*-maet or a RE2 regular expression prefixed by regex:. Every
+// kind of pattern is matched against the normalized, case folded values.
+type Pattern struct {
+	raw  string
+	kind patternKind
+	// folded is the literal or the glob compared with the folded values
+	folded       string
+	regexp       *regexp.Regexp
+	foldedRegexp *regexp.Regexp
+}
+
+// CompilePattern parses the entry, refusing the patterns too expensive
+// to be evaluated inside the policy.
+func CompilePattern(raw string) (Pattern, error) {
+	if len(raw) > maxPatternLength {
+		return Pattern{}, InvalidPatternError{
+			Pattern: raw,
+			Reason:  fmt.Sprintf("longer than %d characters", maxPatternLength),
+		}
+	}
+
+	if expression, found := strings.CutPrefix(raw, regexPatternPrefix); found {
+		return compileRegexPattern(raw, expression)
+	}
+
+	if strings.ContainsAny(raw, globMetaCharacters) {
+		return compileGlobPattern(raw)
+	}
+
+	return Pattern{raw: raw, kind: literalPattern, folded: word.Fold(raw)}, nil
+}
+
+func compileGlobPattern(raw string) (Pattern, error) {
+	if strings.Count(raw, "*") > maxGlobWildcards {
+		return Pattern{}, InvalidPatternError{
+			Pattern: raw,
+			Reason:  fmt.Sprintf("more than %d wildcards", maxGlobWildcards),
+		}
+	}
+	folded := word.Fold(raw)
+	for _, glob := range []string{raw, folded} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return Pattern{}, InvalidPatternError{Pattern: raw, Reason: err.Error()}
+		}
+	}
+	return Pattern{raw: raw, kind: globPattern, folded: folded}, nil
+}
+
+func compileRegexPattern(raw, expression string) (Pattern, error) {
+	// the whole key has to match the expression
+	anchored := "^(?:" + expression + ")$"
+	parsed, err := syntax.Parse(anchored, syntax.Perl)
+	if err != nil {
+		return Pattern{}, InvalidPatternError{Pattern: raw, Reason: err.Error()}
+	}
+	if hasLargeRepeat(parsed) {
+		return Pattern{}, InvalidPatternError{
+			Pattern: raw,
+			Reason:  fmt.Sprintf("repetitions above %d are not allowed", maxRegexRepeatCount),
+		}
+	}
+	program, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return Pattern{}, InvalidPatternError{Pattern: raw, Reason: err.Error()}
+	}
+	if len(program.Inst) > maxRegexProgramInsts {
+		return Pattern{}, InvalidPatternError{Pattern: raw, Reason: "the expression is too complex"}
+	}
+
+	compiled, err := regexp.Compile(anchored)
+	if err != nil {
+		return Pattern{}, InvalidPatternError{Pattern: raw, Reason: err.Error()}
+	}
+	// the folded values are lowercase, the expression is matched ignoring
+	// the case like the literals and the globs
+	folded, err := regexp.Compile("(?i)" + anchored)
+	if err != nil {
+		return Pattern{}, InvalidPatternError{Pattern: raw, Reason: err.Error()}
+	}
+	return Pattern{raw: raw, kind: regexPattern, regexp: compiled, foldedRegexp: folded}, nil
+}
+
+func hasLargeRepeat(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpRepeat && (re.Min > maxRegexRepeatCount || re.Max > maxRegexRepeatCount) {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasLargeRepeat(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLiteral reports whether the pattern matches a single value.
+func (p Pattern) IsLiteral() bool {
+	return p.kind == literalPattern
+}
+
+func (p Pattern) String() string {
+	return p.raw
+}
+
+// Match reports whether the pattern matches the normalized, case folded
+// form of the value.
+func (p Pattern) Match(value string) bool {
+	return p.matchFolded(word.Fold(value))
+}
+
+func (p Pattern) matchFolded(folded string) bool {
+	switch p.kind {
+	case globPattern:
+		matched, err := path.Match(p.folded, folded)
+		return err == nil && matched
+	case regexPattern:
+		return p.foldedRegexp.MatchString(folded)
+	case literalPattern:
+		return p.folded == folded
+	}
+	return false
+}
+
+// MatchExactly reports whether the pattern matches the value byte by byte
+// instead of its normalized form, for the values that are case sensitive
+// like the Kubernetes identities.
+func (p Pattern) MatchExactly(value string) bool {
+	switch p.kind {
+	case globPattern:
+		matched, err := path.Match(p.raw, value)
+		return err == nil && matched
+	case regexPattern:
+		return p.regexp.MatchString(value)
+	case literalPattern:
+		return p.raw == value
+	}
+	return false
+}
+
+// Overlaps reports whether the patterns could match the same value. A
+// literal is matched against the other pattern, two globs or regular
+// expressions overlap unless their literal prefixes or suffixes differ.
+func (p Pattern) Overlaps(other Pattern) bool {
+	switch {
+	case p.IsLiteral():
+		return other.Match(p.raw)
+	case other.IsLiteral():
+		return p.Match(other.raw)
+	}
+	prefix, suffix := p.literalAffixes()
+	otherPrefix, otherSuffix := other.literalAffixes()
+	return (strings.HasPrefix(prefix, otherPrefix) || strings.HasPrefix(otherPrefix, prefix)) &&
+		(strings.HasSuffix(suffix, otherSuffix) || strings.HasSuffix(otherSuffix, suffix))
+}
+
+// literalAffixes returns the folded text every value matched by the
+// pattern starts and ends with. They can be shorter than the real ones,
+// never longer: only their ASCII characters are kept, the folding of the
+// other ones could change the characters around them.
+func (p Pattern) literalAffixes() (string, string) {
+	var prefix, suffix string
+	switch p.kind {
+	case globPattern:
+		// the escapes are not unquoted, they end the affixes too
+		start := strings.IndexAny(p.folded, globMetaCharacters+"\\")
+		end := strings.LastIndexAny(p.folded, globMetaCharacters+"\\]")
+		prefix, suffix = p.folded[:start], p.folded[end+1:]
+	case regexPattern:
+		expression := strings.TrimPrefix(p.raw, regexPatternPrefix)
+		parsed, err := syntax.Parse(expression, syntax.Perl)
+		if err != nil {
+			return "", ""
+		}
+		prefix, suffix = regexLiteralAffixes(parsed.Simplify())
+	case literalPattern:
+		prefix, suffix = p.folded, p.folded
+	}
+	if i := strings.IndexFunc(prefix, isNotASCII); i >= 0 {
+		prefix = prefix[:i]
+	}
+	if i := strings.LastIndexFunc(suffix, isNotASCII); i >= 0 {
+		_, size := utf8.DecodeRuneInString(suffix[i:])
+		suffix = suffix[i+size:]
+	}
+	return strings.ToLower(prefix), strings.ToLower(suffix)
+}
+
+func isNotASCII(r rune) bool {
+	return r >= utf8.RuneSelf
+}
+
+// regexLiteralAffixes returns the literal characters the expression starts
+// and ends with.
+func regexLiteralAffixes(re *syntax.Regexp) (string, string) {
+	if re.Op == syntax.OpLiteral {
+		return string(re.Rune), string(re.Rune)
+	}
+	if re.Op != syntax.OpConcat {
+		return "", ""
+	}
+
+	var prefix, suffix []rune
+	subs := re.Sub
+	for len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		subs = subs[1:]
+	}
+	for _, sub := range subs {
+		if sub.Op != syntax.OpLiteral {
+			break
+		}
+		prefix = append(prefix, sub.Rune...)
+	}
+	subs = re.Sub
+	for len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText {
+		subs = subs[:len(subs)-1]
+	}
+	for i := len(subs) - 1; i >= 0 && subs[i].Op == syntax.OpLiteral; i-- {
+		suffix = append(append([]rune{}, subs[i].Rune...), suffix...)
+	}
+	return string(prefix), string(suffix)
+}
+
+type Patterns []Pattern
+
+func CompilePatterns(raws []string) (Patterns, error) {
+	patterns := make(Patterns, 0, len(raws))
+	for _, raw := range raws {
+		pattern, err := CompilePattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// MatchAny reports whether at least one pattern matches the value.
+func (p Patterns) MatchAny(value string) bool {
+	if len(p) == 0 {
+		return false
+	}
+	folded := word.Fold(value)
+	for _, pattern := range p {
+		if pattern.matchFolded(folded) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAnyExactly reports whether at least one pattern matches exactly the
+// value.
+func (p Patterns) MatchAnyExactly(value string) bool {
+	for _, pattern := range p {
+		if pattern.MatchExactly(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileValidPatterns compiles the entries skipping the invalid ones,
+// the settings have already been checked by validate_settings.
+func compileValidPatterns(raws []string) Patterns {
+	patterns := make(Patterns, 0, len(raws))
+	for _, raw := range raws {
+		if pattern, err := CompilePattern(raw); err == nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
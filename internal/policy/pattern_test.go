@@ -0,0 +1,198 @@
+package policy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternMatch(t *testing.T) {
+	type testCase struct {
+		name            string
+		pattern         string
+		value           string
+		expectedLiteral bool
+		expectedMatch   bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:            "literal matches the same value",
+			pattern:         "level",
+			value:           "level",
+			expectedLiteral: true,
+			expectedMatch:   true,
+		},
+		{
+			name:            "literal does not match a different value",
+			pattern:         "level",
+			value:           "levels",
+			expectedLiteral: true,
+			expectedMatch:   false,
+		},
+		{
+			name:          "glob matches a family of keys",
+			pattern:       "team-*-maet",
+			value:         "team-abc-maet",
+			expectedMatch: true,
+		},
+		{
+			name:          "glob matches everything under a vendor prefix",
+			pattern:       "example.com/*",
+			value:         "example.com/aba",
+			expectedMatch: true,
+		},
+		{
+			name:          "glob does not match other keys",
+			pattern:       "team-*-maet",
+			value:         "team-abc",
+			expectedMatch: false,
+		},
+		{
+			name:          "regex matches the whole value",
+			pattern:       `regex:[a-z]+\.example\.com/.+`,
+			value:         "aba.example.com/x",
+			expectedMatch: true,
+		},
+		{
+			name:          "glob matches the case folded value",
+			pattern:       "Team-*",
+			value:         "TEAM-x",
+			expectedMatch: true,
+		},
+		{
+			name:          "regex matches the case folded value",
+			pattern:       "regex:Team-[a-z]+",
+			value:         "TEAM-X",
+			expectedMatch: true,
+		},
+		{
+			name:            "literal matches the normalized value",
+			pattern:         "ﬁx",
+			value:           "FIX",
+			expectedLiteral: true,
+			expectedMatch:   true,
+		},
+		{
+			name:          "regex is anchored",
+			pattern:       "regex:aba",
+			value:         "abaaba",
+			expectedMatch: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := policy.CompilePattern(tc.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLiteral, pattern.IsLiteral())
+			assert.Equal(t, tc.expectedMatch, pattern.Match(tc.value))
+		})
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	type testCase struct {
+		name                 string
+		pattern              string
+		expectedErrorContent string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:                 "invalid regex",
+			pattern:              "regex:(aba",
+			expectedErrorContent: "missing closing )",
+		},
+		{
+			name:                 "invalid glob",
+			pattern:              "team-[a",
+			expectedErrorContent: "syntax error in pattern",
+		},
+		{
+			name:                 "too long pattern",
+			pattern:              strings.Repeat("a", 257),
+			expectedErrorContent: "longer than 256 characters",
+		},
+		{
+			name:                 "glob with too many wildcards",
+			pattern:              "*a*b*c*d*e*f*g*h*",
+			expectedErrorContent: "more than 8 wildcards",
+		},
+		{
+			name:                 "regex with a large repetition",
+			pattern:              "regex:a{1000}",
+			expectedErrorContent: "repetitions above 100 are not allowed",
+		},
+		{
+			name:                 "regex with a large program",
+			pattern:              "regex:(" + strings.Repeat("ab", 100) + "){20}",
+			expectedErrorContent: "too complex",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := policy.CompilePattern(tc.pattern)
+			var patternErr policy.InvalidPatternError
+			require.ErrorAs(t, err, &patternErr)
+			assert.Equal(t, tc.pattern, patternErr.Pattern)
+			assert.ErrorContains(t, err, tc.expectedErrorContent)
+		})
+	}
+}
+
+func TestPatternOverlaps(t *testing.T) {
+	type testCase struct {
+		name             string
+		pattern          string
+		other            string
+		expectedOverlaps bool
+	}
+
+	for _, tc := range []testCase{
+		{name: "same literals", pattern: "aba", other: "aba", expectedOverlaps: true},
+		{name: "different literals", pattern: "aba", other: "level", expectedOverlaps: false},
+		{name: "literal matched by a glob", pattern: "kube-system", other: "kube-*", expectedOverlaps: true},
+		{name: "literal not matched by a glob", pattern: "default", other: "kube-*", expectedOverlaps: false},
+		{name: "globs with compatible affixes", pattern: "kube-*", other: "*-system", expectedOverlaps: true},
+		{name: "globs with different prefixes", pattern: "kube-*", other: "team-*", expectedOverlaps: false},
+		{name: "globs with different suffixes", pattern: "*-system", other: "*-public", expectedOverlaps: false},
+		{name: "globs with classes", pattern: "team-[ab]", other: "tenant-*", expectedOverlaps: false},
+		{name: "glob and regex with compatible affixes", pattern: "kube-*", other: "regex:.+-system", expectedOverlaps: true},
+		{name: "glob and regex with different prefixes", pattern: "kube-*", other: "regex:team-.+", expectedOverlaps: false},
+		{name: "regexes with compatible affixes", pattern: "regex:kube-.+", other: "regex:.*system", expectedOverlaps: true},
+		{name: "regexes with different suffixes", pattern: "regex:.+-a", other: "regex:.+-b", expectedOverlaps: false},
+		{name: "case insensitive regex", pattern: "regex:(?i)kube-.+", other: "regex:KUBE-.+", expectedOverlaps: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := policy.CompilePattern(tc.pattern)
+			require.NoError(t, err)
+			other, err := policy.CompilePattern(tc.other)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOverlaps, pattern.Overlaps(other))
+			assert.Equal(t, tc.expectedOverlaps, other.Overlaps(pattern))
+		})
+	}
+}
+
+func TestPatternsMatchAny(t *testing.T) {
+	patterns, err := policy.CompilePatterns([]string{"aba", "team-*-maet"})
+	require.NoError(t, err)
+
+	assert.True(t, patterns.MatchAny("aba"))
+	assert.True(t, patterns.MatchAny("team-x-maet"))
+	assert.False(t, patterns.MatchAny("level"))
+}
+
+func TestPatternsMatchAnyExactly(t *testing.T) {
+	patterns, err := policy.CompilePatterns([]string{"admin", "ci-*"})
+	require.NoError(t, err)
+
+	assert.True(t, patterns.MatchAnyExactly("admin"))
+	assert.True(t, patterns.MatchAnyExactly("ci-runner"))
+	assert.False(t, patterns.MatchAnyExactly("ADMIN"))
+	assert.False(t, patterns.MatchAnyExactly("\uff41\uff44\uff4d\uff49\uff4e"))
+	assert.False(t, patterns.MatchAnyExactly("CI-runner"))
+	assert.True(t, patterns.MatchAny("CI-runner"))
+	assert.True(t, patterns.MatchAny("ADMIN"))
+}
@@ -0,0 +1,47 @@
+package policy
+
+import "fmt"
+
+// PalindromeDetection selects the detectors flagging the palindrome keys.
+type PalindromeDetection string
+
+const (
+	// DetectionMirror flags the keys reading the same backward.
+	DetectionMirror PalindromeDetection = "mirror"
+	// DetectionPermutation flags the keys whose characters can be
+	// rearranged in a palindrome, like ivicc.
+	DetectionPermutation PalindromeDetection = "permutation"
+	// DetectionBoth applies both the detectors.
+	DetectionBoth PalindromeDetection = "both"
+)
+
+type InvalidPalindromeDetectionError struct {
+	Detection PalindromeDetection
+}
+
+func (e InvalidPalindromeDetectionError) Error() string {
+	return fmt.Sprintf(
+		"%s is not a valid palindrome detection, it must be one of %s, %s, %s",
+		e.Detection,
+		DetectionMirror,
+		DetectionPermutation,
+		DetectionBoth,
+	)
+}
+
+func (d PalindromeDetection) Validate() error {
+	switch d {
+	case "", DetectionMirror, DetectionPermutation, DetectionBoth:
+		return nil
+	default:
+		return InvalidPalindromeDetectionError{Detection: d}
+	}
+}
+
+func (s *Settings) detectsMirror() bool {
+	return s.PalindromeDetection != DetectionPermutation
+}
+
+func (s *Settings) detectsPermutation() bool {
+	return s.PalindromeDetection == DetectionPermutation || s.PalindromeDetection == DetectionBoth
+}
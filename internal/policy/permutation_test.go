@@ -0,0 +1,188 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePermutationPalindromes(t *testing.T) {
+	type testCase struct {
+		name               string
+		settings           policy.Settings
+		labelKey           string
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "mirror detection by default",
+			settings: policy.Settings{},
+			labelKey: "ivicc",
+		},
+		{
+			name:     "permutation palindrome flagged by the permutation detection",
+			settings: policy.Settings{PalindromeDetection: policy.DetectionPermutation},
+			labelKey: "ivicc",
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "ivicc",
+					Path:   "metadata.labels.ivicc",
+					Source: policy.SourceLabel,
+					Rule:   policy.RulePermutationPalindrome,
+					Value:  "icvci",
+				},
+			},
+		},
+		{
+			name:     "palindrome flagged by the permutation detector instead of the mirror one",
+			settings: policy.Settings{PalindromeDetection: policy.DetectionPermutation},
+			labelKey: "civic",
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "civic",
+					Path:   "metadata.labels.civic",
+					Source: policy.SourceLabel,
+					Rule:   policy.RulePermutationPalindrome,
+					Value:  "civic",
+				},
+			},
+		},
+		{
+			name:     "palindrome flagged by the mirror detector when both are applied",
+			settings: policy.Settings{PalindromeDetection: policy.DetectionBoth},
+			labelKey: "civic",
+			expectedViolations: []policy.Violation{
+				{Key: "civic", Path: "metadata.labels.civic", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:     "permutation palindrome flagged when both the detectors are applied",
+			settings: policy.Settings{PalindromeDetection: policy.DetectionBoth},
+			labelKey: "aabb",
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "aabb",
+					Path:   "metadata.labels.aabb",
+					Source: policy.SourceLabel,
+					Rule:   policy.RulePermutationPalindrome,
+					Value:  "abba",
+				},
+			},
+		},
+		{
+			name:     "key whose characters cannot be rearranged in a palindrome",
+			settings: policy.Settings{PalindromeDetection: policy.DetectionBoth},
+			labelKey: "nginx",
+		},
+		{
+			name: "permutation palindrome allowed",
+			settings: policy.Settings{
+				PalindromeDetection: policy.DetectionPermutation,
+				AllowedPalindromes:  []string{"ivicc"},
+			},
+			labelKey: "ivicc",
+		},
+		{
+			name: "permutation palindrome shorter than the minimum length",
+			settings: policy.Settings{
+				PalindromeDetection: policy.DetectionPermutation,
+				MinLength:           5,
+			},
+			labelKey: "aabb",
+		},
+		{
+			name: "permutation palindrome in the name of the key",
+			settings: policy.Settings{
+				PalindromeDetection: policy.DetectionPermutation,
+				LabelKeyScope:       policy.LabelKeyScopeName,
+			},
+			labelKey: "example.com/ivicc",
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "example.com/ivicc",
+					Path:   `metadata.labels["example.com/ivicc"]`,
+					Source: policy.SourceLabel,
+					Rule:   policy.RulePermutationPalindrome,
+					Value:  "icvci",
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+				Object: []byte(`{"metadata": {"labels": {"` + tc.labelKey + `": "x"}}}`),
+			}
+			settings := tc.settings
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestPermutationPalindromeViolationMessage(t *testing.T) {
+	err := policy.ViolationsError{
+		Violations: []policy.Violation{
+			{
+				Key:    "ivicc",
+				Path:   "metadata.labels.ivicc",
+				Source: policy.SourceLabel,
+				Rule:   policy.RulePermutationPalindrome,
+				Value:  "icvci",
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		`label with key ivicc at metadata.labels.ivicc not allowed, the characters of the word can be rearranged in the palindrome "icvci"`, //nolint:lll
+		err.Error(),
+	)
+}
+
+func TestPalindromeDetectionSettingsValidation(t *testing.T) {
+	type testCase struct {
+		name          string
+		settings      policy.Settings
+		expectedError error
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:          "unknown palindrome detection",
+			settings:      policy.Settings{PalindromeDetection: "anagram"},
+			expectedError: policy.InvalidPalindromeDetectionError{Detection: "anagram"},
+		},
+		{
+			name:          "permutation palindrome allowed by the mirror detection",
+			settings:      policy.Settings{AllowedPalindromes: []string{"ivicc"}},
+			expectedError: policy.AllowedPalindromeError{Field: "ivicc"},
+		},
+		{
+			name: "permutation palindrome allowed by the permutation detection",
+			settings: policy.Settings{
+				PalindromeDetection: policy.DetectionPermutation,
+				AllowedPalindromes:  []string{"ivicc"},
+			},
+			expectedError: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			err := settings.Validate()
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+// PodSpecSettings enables the validation of the identifiers of the pod
+// spec, of the pods and of the workload pod templates, each field family
+// can be switched on separately.
+type PodSpecSettings struct {
+	ContainerNames          bool `json:"container_names,omitempty"`
+	InitContainerNames      bool `json:"init_container_names,omitempty"`
+	EphemeralContainerNames bool `json:"ephemeral_container_names,omitempty"`
+	// Names of the ports of every kind of container.
+	PortNames bool `json:"port_names,omitempty"`
+	// Names of the environment variables of every kind of container.
+	EnvNames    bool `json:"env_names,omitempty"`
+	VolumeNames bool `json:"volume_names,omitempty"`
+	// Palindrome identifiers accepted by the policy, by exact name or pattern.
+	// The allowed palindromes of the labels do not apply to the pod spec.
+	AllowedPalindromes []string `json:"allowed_palindromes,omitempty"`
+}
+
+func (p *PodSpecSettings) Validate() error {
+	_, err := CompilePatterns(p.AllowedPalindromes)
+	return err
+}
+
+// podSpecPath returns the gjson path of the pod spec of the given kind: the
+// spec of the pods and the spec of the pod template of the workloads.
+func podSpecPath(kind string) (string, bool) {
+	if kind == "Pod" {
+		return "spec", true
+	}
+	if templatePath, found := podTemplatePath(kind); found {
+		return templatePath + ".spec", true
+	}
+	return "", false
+}
+
+func (s *Settings) isAnAllowedPodSpecPalindrome(palindrome string) bool {
+	if s.allowedPodSpecPalindromePatterns == nil {
+		s.allowedPodSpecPalindromePatterns = compileValidPatterns(s.PodSpec.AllowedPalindromes)
+	}
+	return s.allowedPodSpecPalindromePatterns.MatchAny(palindrome)
+}
+
+// podSpecScanner walks the pod spec collecting the violations of the
+// enabled field families. Every field is tracked both with its gjson path,
+// to read it, and with its reported path, like spec.containers[1].ports[0].name.
+type podSpecScanner struct {
+	settings            *Settings
+	object              gjson.Result
+	oldObject           gjson.Result
+	grandfatherOldNames bool
+	violations          []Violation
+}
+
+// podSpecViolations checks the pod spec identifiers of the object, skipping
+// the unchanged ones of an update when only new violations are reported.
+func (s *Settings) podSpecViolations(
+	kind string,
+	object gjson.Result,
+	oldObject gjson.Result,
+	grandfatherOldNames bool,
+) []Violation {
+	specPath, found := podSpecPath(kind)
+	if s.PodSpec == nil || !found {
+		return nil
+	}
+
+	scanner := podSpecScanner{
+		settings:            s,
+		object:              object,
+		oldObject:           oldObject,
+		grandfatherOldNames: grandfatherOldNames,
+	}
+	scanner.scan(specPath)
+	return scanner.violations
+}
+
+func (p *podSpecScanner) scan(specPath string) {
+	podSpec := p.settings.PodSpec
+	containerLists := []struct {
+		field   string
+		source  Source
+		enabled bool
+	}{
+		{field: "containers", source: SourceContainer, enabled: podSpec.ContainerNames},
+		{field: "initContainers", source: SourceInitContainer, enabled: podSpec.InitContainerNames},
+		{field: "ephemeralContainers", source: SourceEphemeralContainer, enabled: podSpec.EphemeralContainerNames},
+	}
+
+	for _, list := range containerLists {
+		p.forEachItem(specPath, specPath, list.field, func(containerPath, containerGJSONPath string) {
+			if list.enabled {
+				p.check(containerPath, containerGJSONPath, list.source)
+			}
+			if podSpec.PortNames {
+				p.forEachItem(containerPath, containerGJSONPath, "ports", func(portPath, portGJSONPath string) {
+					p.check(portPath, portGJSONPath, SourcePort)
+				})
+			}
+			if podSpec.EnvNames {
+				p.forEachItem(containerPath, containerGJSONPath, "env", func(envPath, envGJSONPath string) {
+					p.check(envPath, envGJSONPath, SourceEnv)
+				})
+			}
+		})
+	}
+
+	if podSpec.VolumeNames {
+		p.forEachItem(specPath, specPath, "volumes", func(volumePath, volumeGJSONPath string) {
+			p.check(volumePath, volumeGJSONPath, SourceVolume)
+		})
+	}
+}
+
+// forEachItem calls the function with the paths of every item of the list
+// found in the field of the parent.
+func (p *podSpecScanner) forEachItem(
+	parentPath string,
+	parentGJSONPath string,
+	field string,
+	function func(itemPath, itemGJSONPath string),
+) {
+	p.object.Get(parentGJSONPath + "." + field).ForEach(func(index, _ gjson.Result) bool {
+		function(
+			fmt.Sprintf("%s.%s[%d]", parentPath, field, index.Int()),
+			fmt.Sprintf("%s.%s.%d", parentGJSONPath, field, index.Int()),
+		)
+		return true
+	})
+}
+
+// check validates the name of the item.
+func (p *podSpecScanner) check(itemPath, itemGJSONPath string, source Source) {
+	namePath := itemGJSONPath + ".name"
+	name := p.object.Get(namePath).String()
+	if p.grandfatherOldNames && p.oldObject.Get(namePath).String() == name {
+		return
+	}
+	p.violations = append(p.violations, p.settings.detectorViolations(
+		name,
+		Violation{Key: name, Path: itemPath + ".name", Source: source},
+	)...)
+}
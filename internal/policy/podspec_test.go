@@ -0,0 +1,246 @@
+package policy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	"github.com/francoispqt/onelog"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const podSpecWithPalindromes = `{
+	"containers": [
+		{"name": "app", "ports": [{"containerPort": 80}]},
+		{
+			"name": "kayak",
+			"ports": [{"name": "http", "containerPort": 8080}, {"name": "sas", "containerPort": 9090}],
+			"env": [{"name": "ABBA", "value": "1"}, {"name": "MODE", "value": "2"}]
+		}
+	],
+	"initContainers": [{"name": "tenet", "ports": [{"name": "pip", "containerPort": 5000}]}],
+	"ephemeralContainers": [{"name": "debugged", "env": [{"name": "X", "value": "3"}]}],
+	"volumes": [{"name": "data", "emptyDir": {}}, {"name": "refer", "emptyDir": {}}]
+}`
+
+func TestValidatePodSpec(t *testing.T) {
+	allFamilies := policy.PodSpecSettings{
+		ContainerNames:          true,
+		InitContainerNames:      true,
+		EphemeralContainerNames: true,
+		PortNames:               true,
+		EnvNames:                true,
+		VolumeNames:             true,
+	}
+
+	type testCase struct {
+		name               string
+		kind               string
+		object             string
+		podSpec            *policy.PodSpecSettings
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:    "every field family of a pod",
+			kind:    "Pod",
+			object:  `{"spec": ` + podSpecWithPalindromes + `}`,
+			podSpec: &allFamilies,
+			expectedViolations: []policy.Violation{
+				{Key: "ABBA", Path: "spec.containers[1].env[0].name", Source: policy.SourceEnv, Rule: policy.RulePalindrome},
+				{
+					Key:    "X",
+					Path:   "spec.ephemeralContainers[0].env[0].name",
+					Source: policy.SourceEnv,
+					Rule:   policy.RulePalindrome,
+				},
+				{Key: "kayak", Path: "spec.containers[1].name", Source: policy.SourceContainer, Rule: policy.RulePalindrome},
+				{
+					Key:    "pip",
+					Path:   "spec.initContainers[0].ports[0].name",
+					Source: policy.SourcePort,
+					Rule:   policy.RulePalindrome,
+				},
+				{Key: "refer", Path: "spec.volumes[1].name", Source: policy.SourceVolume, Rule: policy.RulePalindrome},
+				{Key: "sas", Path: "spec.containers[1].ports[1].name", Source: policy.SourcePort, Rule: policy.RulePalindrome},
+				{
+					Key:    "tenet",
+					Path:   "spec.initContainers[0].name",
+					Source: policy.SourceInitContainer,
+					Rule:   policy.RulePalindrome,
+				},
+			},
+		},
+		{
+			name:   "only the enabled field families",
+			kind:   "Pod",
+			object: `{"spec": ` + podSpecWithPalindromes + `}`,
+			podSpec: &policy.PodSpecSettings{
+				ContainerNames: true,
+				VolumeNames:    true,
+			},
+			expectedViolations: []policy.Violation{
+				{Key: "kayak", Path: "spec.containers[1].name", Source: policy.SourceContainer, Rule: policy.RulePalindrome},
+				{Key: "refer", Path: "spec.volumes[1].name", Source: policy.SourceVolume, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:   "identifiers allowed by the pod spec allowlist",
+			kind:   "Pod",
+			object: `{"spec": ` + podSpecWithPalindromes + `}`,
+			podSpec: &policy.PodSpecSettings{
+				ContainerNames:     true,
+				InitContainerNames: true,
+				AllowedPalindromes: []string{"kayak", "tenet"},
+			},
+		},
+		{
+			name:   "pod template of a deployment",
+			kind:   "Deployment",
+			object: `{"spec": {"template": {"spec": ` + podSpecWithPalindromes + `}}}`,
+			podSpec: &policy.PodSpecSettings{
+				EphemeralContainerNames: true,
+				PortNames:               true,
+			},
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "pip",
+					Path:   "spec.template.spec.initContainers[0].ports[0].name",
+					Source: policy.SourcePort,
+					Rule:   policy.RulePalindrome,
+				},
+				{
+					Key:    "sas",
+					Path:   "spec.template.spec.containers[1].ports[1].name",
+					Source: policy.SourcePort,
+					Rule:   policy.RulePalindrome,
+				},
+			},
+		},
+		{
+			name:   "pod template of a cronjob",
+			kind:   "CronJob",
+			object: `{"spec": {"jobTemplate": {"spec": {"template": {"spec": ` + podSpecWithPalindromes + `}}}}}`,
+			podSpec: &policy.PodSpecSettings{
+				VolumeNames: true,
+			},
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "refer",
+					Path:   "spec.jobTemplate.spec.template.spec.volumes[1].name",
+					Source: policy.SourceVolume,
+					Rule:   policy.RulePalindrome,
+				},
+			},
+		},
+		{
+			name:    "kinds without a pod spec",
+			kind:    "Service",
+			object:  `{"spec": ` + podSpecWithPalindromes + `}`,
+			podSpec: &allFamilies,
+		},
+		{
+			name:    "pod spec not checked by default",
+			kind:    "Pod",
+			object:  `{"spec": ` + podSpecWithPalindromes + `}`,
+			podSpec: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: tc.kind},
+				Object: []byte(tc.object),
+			}
+
+			err := policy.ValidateLabels(&request, &policy.Settings{PodSpec: tc.podSpec})
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestValidatePodSpecGrandfathersUnchangedNames(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:      kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+		Operation: "UPDATE",
+		Object:    []byte(`{"spec": {"containers": [{"name": "kayak"}], "ephemeralContainers": [{"name": "stats"}]}}`),
+		OldObject: []byte(`{"spec": {"containers": [{"name": "kayak"}]}}`),
+	}
+	settings := policy.Settings{
+		PodSpec:           &policy.PodSpecSettings{ContainerNames: true, EphemeralContainerNames: true},
+		NewViolationsOnly: true,
+	}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{
+			Key:    "stats",
+			Path:   "spec.ephemeralContainers[0].name",
+			Source: policy.SourceEphemeralContainer,
+			Rule:   policy.RulePalindrome,
+		},
+	}, violationsErr.Violations)
+	assert.Equal(
+		t,
+		"ephemeral container name stats at spec.ephemeralContainers[0].name not allowed, the word is a palindrome",
+		violationsErr.Error(),
+	)
+}
+
+func TestValidateEphemeralContainersSubresource(t *testing.T) {
+	settingsRaw, err := json.Marshal(policy.Settings{
+		PodSpec:           &policy.PodSpecSettings{EphemeralContainerNames: true},
+		NewViolationsOnly: true,
+	})
+	require.NoError(t, err)
+	// kubectl debug adds the ephemeral containers updating the subresource
+	// of the pod, the object is the whole pod
+	payload, err := json.Marshal(kubewarden_protocol.ValidationRequest{
+		Request: kubewarden_protocol.KubernetesAdmissionRequest{
+			Kind:        kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+			Resource:    kubewarden_protocol.GroupVersionResource{Version: "v1", Kind: "pods"},
+			SubResource: "ephemeralcontainers",
+			Operation:   "UPDATE",
+			Object: []byte(`{
+				"metadata": {"name": "web", "labels": {"app": "web"}},
+				"spec": {"containers": [{"name": "app"}], "ephemeralContainers": [{"name": "debugger-stats"}, {"name": "stats"}]}
+			}`),
+			OldObject: []byte(`{
+				"metadata": {"name": "web", "labels": {"app": "web"}},
+				"spec": {"containers": [{"name": "app"}], "ephemeralContainers": [{"name": "debugger-stats"}]}
+			}`),
+		},
+		Settings: settingsRaw,
+	})
+	require.NoError(t, err)
+
+	result, err := policy.NewValidate(&onelog.Logger{})(payload)
+	require.NoError(t, err)
+
+	var response kubewarden_protocol.ValidationResponse
+	require.NoError(t, json.Unmarshal(result, &response))
+	assert.False(t, response.Accepted)
+	assert.Equal(
+		t,
+		"ephemeral container name stats at spec.ephemeralContainers[1].name not allowed, the word is a palindrome",
+		*response.Message,
+	)
+}
+
+func TestPodSpecSettingsValidation(t *testing.T) {
+	settings := policy.Settings{PodSpec: &policy.PodSpecSettings{AllowedPalindromes: []string{"nginx"}}}
+
+	require.ErrorIs(t, settings.Validate(), policy.AllowedPalindromeError{Field: "nginx"})
+}
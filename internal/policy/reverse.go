@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"strings"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/tidwall/gjson"
+)
+
+// reversePairScanner collects the reverse pairs of the labels maps of the
+// admitted object.
+type reversePairScanner struct {
+	settings           *Settings
+	oldObject          gjson.Result
+	grandfatherOldKeys bool
+	violations         []Violation
+}
+
+// reversePairViolations returns the violations of the labels that are the
+// reverse of another label key of the same map, or of their own value, when
+// the reverse pairs are rejected. Every pair of keys is reported once, on
+// its last member, naming the other one.
+// When only new violations are reported, the pairs whose members were both
+// already present in the old object are skipped.
+func (s *Settings) reversePairViolations(
+	kind string,
+	object gjson.Result,
+	oldObject gjson.Result,
+	grandfatherOldKeys bool,
+) []Violation {
+	if !s.RejectReversePairs {
+		return nil
+	}
+
+	scanner := reversePairScanner{settings: s, oldObject: oldObject, grandfatherOldKeys: grandfatherOldKeys}
+	for _, path := range labelsPaths(kind) {
+		scanner.scan(path, object.Get(path))
+	}
+	return scanner.violations
+}
+
+// scan checks every key of the labels map found at the path against the
+// keys before it and against its value.
+func (r *reversePairScanner) scan(path string, labels gjson.Result) {
+	// the first key of every normalized form, the later keys with the same
+	// form are duplicates of it
+	keys := make(map[string]string)
+	labels.ForEach(func(key, value gjson.Result) bool {
+		form := r.settings.reversePairForm(key.String())
+		if word.Length(form) < r.settings.MinLength {
+			return true
+		}
+
+		normalized := strings.Join(word.Normalize(form), "")
+		reversed := word.Reverse(form)
+		// a palindrome is its own reverse
+		if pair, found := keys[reversed]; found && reversed != normalized {
+			r.checkKeyPair(path, key.String(), pair)
+		}
+		if _, found := keys[normalized]; !found {
+			keys[normalized] = key.String()
+		}
+		r.checkValuePair(path, key.String(), value.String())
+		return true
+	})
+}
+
+func (r *reversePairScanner) checkKeyPair(path, key, pair string) {
+	if r.grandfatherOldKeys && r.oldObject.Get(gjsonPath(path, key)).Exists() &&
+		r.oldObject.Get(gjsonPath(path, pair)).Exists() {
+		return
+	}
+	keyPath := jsonPath(path, key)
+	r.violations = append(r.violations, Violation{
+		Key:    key,
+		Path:   keyPath,
+		Source: SourceLabel,
+		Rule:   RuleReversePair,
+		Value:  pair,
+	})
+}
+
+func (r *reversePairScanner) checkValuePair(path, key, value string) {
+	oldValue := r.oldObject.Get(gjsonPath(path, key))
+	if r.grandfatherOldKeys && oldValue.Exists() && oldValue.String() == value {
+		return
+	}
+	if !word.IsReversePair(r.settings.reversePairForm(key), r.settings.reversePairForm(value)) {
+		return
+	}
+	r.violations = append(r.violations, Violation{
+		Key:    key,
+		Path:   jsonPath(path, key),
+		Source: SourceLabel,
+		Rule:   RuleReverseValue,
+		Value:  value,
+	})
+}
+
+// reversePairForm returns the form of the word compared by the reverse pair
+// check: its skeleton when requested, without the separators when requested
+// by the match mode.
+func (s *Settings) reversePairForm(w string) string {
+	if s.ConfusableSkeleton {
+		w = word.Skeleton(w)
+	}
+	return s.compared(w)
+}
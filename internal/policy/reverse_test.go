@@ -0,0 +1,173 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReversePairs(t *testing.T) {
+	type testCase struct {
+		name               string
+		settings           policy.Settings
+		labels             string
+		oldLabels          string
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "reverse pairs accepted by default",
+			settings: policy.Settings{},
+			labels:   `{"stressed": "x", "desserts": "x", "live": "evil"}`,
+		},
+		{
+			name:     "reverse pair of keys",
+			settings: policy.Settings{RejectReversePairs: true},
+			labels:   `{"stressed": "x", "desserts": "x"}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "desserts",
+					Path:   "metadata.labels.desserts",
+					Source: policy.SourceLabel,
+					Rule:   policy.RuleReversePair,
+					Value:  "stressed",
+				},
+			},
+		},
+		{
+			name:     "key reverse of its value",
+			settings: policy.Settings{RejectReversePairs: true},
+			labels:   `{"live": "evil"}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "live",
+					Path:   "metadata.labels.live",
+					Source: policy.SourceLabel,
+					Rule:   policy.RuleReverseValue,
+					Value:  "evil",
+				},
+			},
+		},
+		{
+			name:     "reverse pair after the normalization",
+			settings: policy.Settings{RejectReversePairs: true},
+			labels:   `{"Stressed": "x", "DESSERTS": "x"}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "DESSERTS",
+					Path:   "metadata.labels.DESSERTS",
+					Source: policy.SourceLabel,
+					Rule:   policy.RuleReversePair,
+					Value:  "Stressed",
+				},
+			},
+		},
+		{
+			name:     "palindrome key and value are not reverse pairs",
+			settings: policy.Settings{RejectReversePairs: true, AllowedPalindromes: []string{"level"}},
+			labels:   `{"level": "level", "Level": "x"}`,
+		},
+		{
+			name:     "unrelated keys",
+			settings: policy.Settings{RejectReversePairs: true},
+			labels:   `{"app": "nginx", "tier": "web"}`,
+		},
+		{
+			name:     "reverse pair shorter than the minimum length",
+			settings: policy.Settings{RejectReversePairs: true, MinLength: 3},
+			labels:   `{"on": "no"}`,
+		},
+		{
+			name: "reverse pair ignoring the separators",
+			settings: policy.Settings{
+				RejectReversePairs: true,
+				MatchMode:          policy.MatchIgnoreSeparators,
+			},
+			labels: `{"stres-sed": "x", "des_serts": "x"}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "des_serts",
+					Path:   "metadata.labels.des_serts",
+					Source: policy.SourceLabel,
+					Rule:   policy.RuleReversePair,
+					Value:  "stres-sed",
+				},
+			},
+		},
+		{
+			name:      "reverse pair already present in the old object",
+			settings:  policy.Settings{RejectReversePairs: true, NewViolationsOnly: true},
+			labels:    `{"stressed": "x", "desserts": "x", "live": "evil"}`,
+			oldLabels: `{"stressed": "x", "desserts": "x", "live": "evil"}`,
+		},
+		{
+			name:      "reverse pair completed by a new key",
+			settings:  policy.Settings{RejectReversePairs: true, NewViolationsOnly: true},
+			labels:    `{"stressed": "x", "desserts": "x"}`,
+			oldLabels: `{"stressed": "x"}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "desserts",
+					Path:   "metadata.labels.desserts",
+					Source: policy.SourceLabel,
+					Rule:   policy.RuleReversePair,
+					Value:  "stressed",
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+				Object: []byte(`{"metadata": {"labels": ` + tc.labels + `}}`),
+			}
+			if tc.oldLabels != "" {
+				request.Operation = "UPDATE"
+				request.OldObject = []byte(`{"metadata": {"labels": ` + tc.oldLabels + `}}`)
+			}
+			settings := tc.settings
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestReversePairViolationMessages(t *testing.T) {
+	err := policy.ViolationsError{
+		Violations: []policy.Violation{
+			{
+				Key:    "desserts",
+				Path:   "metadata.labels.desserts",
+				Source: policy.SourceLabel,
+				Rule:   policy.RuleReversePair,
+				Value:  "stressed",
+			},
+			{
+				Key:    "live",
+				Path:   "metadata.labels.live",
+				Source: policy.SourceLabel,
+				Rule:   policy.RuleReverseValue,
+				Value:  "evil",
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		"label with key desserts at metadata.labels.desserts not allowed, the key is the reverse of the key stressed; "+
+			"label with key live at metadata.labels.live not allowed, the key is the reverse of its value evil",
+		err.Error(),
+	)
+}
@@ -0,0 +1,206 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// selectorForm is the structure of a selector.
+type selectorForm int
+
+const (
+	// selectorFormMap is a plain map of labels, like the Service selector.
+	selectorFormMap selectorForm = iota
+	// selectorFormLabelSelector has matchLabels and matchExpressions.
+	selectorFormLabelSelector
+	// selectorFormNodeSelectorTerm has only label matchExpressions, its
+	// matchFields select node fields and not labels.
+	selectorFormNodeSelectorTerm
+)
+
+// selectorLocation is a selector of an object, the path segments ending
+// with [] are lists whose items are all visited.
+type selectorLocation struct {
+	path string
+	form selectorForm
+}
+
+// selectorLocations returns the locations of the selectors of the given
+// kind, including the ones of the pod spec of the pods and of the workload
+// pod templates.
+func selectorLocations(kind string) []selectorLocation {
+	var locations []selectorLocation
+	switch kind {
+	case "Service":
+		locations = append(locations, selectorLocation{path: "spec.selector", form: selectorFormMap})
+	case "NetworkPolicy":
+		for _, path := range []string{
+			"spec.podSelector",
+			"spec.ingress[].from[].podSelector",
+			"spec.ingress[].from[].namespaceSelector",
+			"spec.egress[].to[].podSelector",
+			"spec.egress[].to[].namespaceSelector",
+		} {
+			locations = append(locations, selectorLocation{path: path, form: selectorFormLabelSelector})
+		}
+	case "PodDisruptionBudget":
+		locations = append(locations, selectorLocation{path: "spec.selector", form: selectorFormLabelSelector})
+	}
+
+	if specPath, found := podSpecPath(kind); found {
+		locations = append(locations, podSpecSelectorLocations(specPath)...)
+	}
+	return locations
+}
+
+func podSpecSelectorLocations(specPath string) []selectorLocation {
+	nodeAffinity := specPath + ".affinity.nodeAffinity"
+	locations := []selectorLocation{
+		{path: specPath + ".nodeSelector", form: selectorFormMap},
+		{
+			path: nodeAffinity + ".requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms[]",
+			form: selectorFormNodeSelectorTerm,
+		},
+		{
+			path: nodeAffinity + ".preferredDuringSchedulingIgnoredDuringExecution[].preference",
+			form: selectorFormNodeSelectorTerm,
+		},
+		{path: specPath + ".topologySpreadConstraints[].labelSelector", form: selectorFormLabelSelector},
+	}
+
+	for _, affinity := range []string{"podAffinity", "podAntiAffinity"} {
+		affinityPath := specPath + ".affinity." + affinity
+		for _, term := range []string{
+			affinityPath + ".requiredDuringSchedulingIgnoredDuringExecution[]",
+			affinityPath + ".preferredDuringSchedulingIgnoredDuringExecution[].podAffinityTerm",
+		} {
+			locations = append(
+				locations,
+				selectorLocation{path: term + ".labelSelector", form: selectorFormLabelSelector},
+				selectorLocation{path: term + ".namespaceSelector", form: selectorFormLabelSelector},
+			)
+		}
+	}
+	return locations
+}
+
+// fieldPath is a field of the object tracked both with its reported path,
+// like spec.ingress[0].from[1].podSelector, and with its gjson path.
+type fieldPath struct {
+	path      string
+	gjsonPath string
+}
+
+func (f fieldPath) child(field string) fieldPath {
+	if f.path == "" {
+		return fieldPath{path: field, gjsonPath: field}
+	}
+	return fieldPath{path: f.path + "." + field, gjsonPath: f.gjsonPath + "." + field}
+}
+
+func (f fieldPath) item(index int64) fieldPath {
+	return fieldPath{
+		path:      fmt.Sprintf("%s[%d]", f.path, index),
+		gjsonPath: fmt.Sprintf("%s.%d", f.gjsonPath, index),
+	}
+}
+
+// resolveListPaths returns the paths of the fields of the object found
+// following the path, visiting every item of the segments ending with [].
+func resolveListPaths(object gjson.Result, path string) []fieldPath {
+	resolved := []fieldPath{{}}
+	for _, segment := range strings.Split(path, ".") {
+		field, isList := strings.CutSuffix(segment, "[]")
+
+		var next []fieldPath
+		for _, parent := range resolved {
+			current := parent.child(field)
+			if !isList {
+				next = append(next, current)
+				continue
+			}
+			object.Get(current.gjsonPath).ForEach(func(index, _ gjson.Result) bool {
+				next = append(next, current.item(index.Int()))
+				return true
+			})
+		}
+		resolved = next
+	}
+	return resolved
+}
+
+// selectorScanner collects the violations of the label keys used by the
+// selectors of the admitted object.
+type selectorScanner struct {
+	settings           *Settings
+	object             gjson.Result
+	oldObject          gjson.Result
+	grandfatherOldKeys bool
+	violations         []Violation
+}
+
+// selectorViolations checks the label keys of the selectors of the object,
+// skipping the ones already used by the same selector of the old object of
+// an update when only new violations are reported.
+func (s *Settings) selectorViolations(
+	kind string,
+	object gjson.Result,
+	oldObject gjson.Result,
+	grandfatherOldKeys bool,
+) []Violation {
+	if !s.CheckSelectors {
+		return nil
+	}
+
+	scanner := selectorScanner{
+		settings:           s,
+		object:             object,
+		oldObject:          oldObject,
+		grandfatherOldKeys: grandfatherOldKeys,
+	}
+	for _, location := range selectorLocations(kind) {
+		for _, selector := range resolveListPaths(object, location.path) {
+			scanner.scan(selector, location.form)
+		}
+	}
+	return scanner.violations
+}
+
+func (s *selectorScanner) scan(selector fieldPath, form selectorForm) {
+	switch form {
+	case selectorFormMap:
+		s.scanMap(selector)
+	case selectorFormLabelSelector:
+		s.scanMap(selector.child("matchLabels"))
+		s.scanExpressions(selector.child("matchExpressions"))
+	case selectorFormNodeSelectorTerm:
+		s.scanExpressions(selector.child("matchExpressions"))
+	}
+}
+
+func (s *selectorScanner) scanMap(labels fieldPath) {
+	s.object.Get(labels.gjsonPath).ForEach(func(key, _ gjson.Result) bool {
+		gjsonKeyPath := gjsonPath(labels.gjsonPath, key.String())
+		if !s.grandfatherOldKeys || !s.oldObject.Get(gjsonKeyPath).Exists() {
+			s.check(key.String(), jsonPath(labels.path, key.String()))
+		}
+		return true
+	})
+}
+
+func (s *selectorScanner) scanExpressions(expressions fieldPath) {
+	s.object.Get(expressions.gjsonPath).ForEach(func(index, expression gjson.Result) bool {
+		key := expression.Get("key").String()
+		keyPath := expressions.item(index.Int()).child("key")
+		if !s.grandfatherOldKeys || s.oldObject.Get(keyPath.gjsonPath).String() != key {
+			s.check(key, keyPath.path)
+		}
+		return true
+	})
+}
+
+func (s *selectorScanner) check(key, path string) {
+	s.violations = append(s.violations, s.settings.keyViolations(key, path, SourceSelector)...)
+}
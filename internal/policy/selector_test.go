@@ -0,0 +1,183 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selectorViolation(key, path string) policy.Violation {
+	return policy.Violation{Key: key, Path: path, Source: policy.SourceSelector, Rule: policy.RulePalindrome}
+}
+
+func TestValidateSelectors(t *testing.T) {
+	type testCase struct {
+		name               string
+		kind               string
+		object             string
+		settings           policy.Settings
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "service selector",
+			kind:     "Service",
+			object:   `{"spec": {"selector": {"app": "web", "level": "debug"}}}`,
+			settings: policy.Settings{CheckSelectors: true},
+			expectedViolations: []policy.Violation{
+				selectorViolation("level", "spec.selector.level"),
+			},
+		},
+		{
+			name: "network policy selectors",
+			kind: "NetworkPolicy",
+			object: `{"spec": {
+				"podSelector": {"matchLabels": {"kayak": "a"}},
+				"ingress": [
+					{"from": [
+						{"ipBlock": {"cidr": "10.0.0.0/8"}},
+						{"namespaceSelector": {"matchExpressions": [
+							{"key": "team", "operator": "Exists"},
+							{"key": "tenet", "operator": "Exists"}
+						]}}
+					]}
+				],
+				"egress": [{"to": [{"podSelector": {"matchLabels": {"example.com/aba": "b"}}}]}]
+			}}`,
+			settings: policy.Settings{CheckSelectors: true, LabelKeyScope: policy.LabelKeyScopeName},
+			expectedViolations: []policy.Violation{
+				selectorViolation("example.com/aba", `spec.egress[0].to[0].podSelector.matchLabels["example.com/aba"]`),
+				selectorViolation("kayak", "spec.podSelector.matchLabels.kayak"),
+				selectorViolation("tenet", "spec.ingress[0].from[1].namespaceSelector.matchExpressions[1].key"),
+			},
+		},
+		{
+			name:     "pod disruption budget selector",
+			kind:     "PodDisruptionBudget",
+			object:   `{"spec": {"minAvailable": 1, "selector": {"matchExpressions": [{"key": "level", "operator": "In"}]}}}`,
+			settings: policy.Settings{CheckSelectors: true},
+			expectedViolations: []policy.Violation{
+				selectorViolation("level", "spec.selector.matchExpressions[0].key"),
+			},
+		},
+		{
+			name: "pod node selector, affinities and topology spread constraints",
+			kind: "Pod",
+			object: `{"spec": {
+				"nodeSelector": {"radar": "x"},
+				"affinity": {
+					"nodeAffinity": {
+						"requiredDuringSchedulingIgnoredDuringExecution": {"nodeSelectorTerms": [
+							{"matchExpressions": [{"key": "rotor", "operator": "In"}], "matchFields": [{"key": "stats"}]}
+						]},
+						"preferredDuringSchedulingIgnoredDuringExecution": [
+							{"weight": 1, "preference": {"matchExpressions": [{"key": "civic", "operator": "In"}]}}
+						]
+					},
+					"podAffinity": {
+						"requiredDuringSchedulingIgnoredDuringExecution": [
+							{"labelSelector": {"matchLabels": {"madam": "y"}}, "topologyKey": "zone"}
+						]
+					},
+					"podAntiAffinity": {
+						"preferredDuringSchedulingIgnoredDuringExecution": [
+							{"weight": 1, "podAffinityTerm": {"namespaceSelector": {"matchLabels": {"refer": "z"}}}}
+						]
+					}
+				},
+				"topologySpreadConstraints": [{"labelSelector": {"matchLabels": {"app": "web", "noon": "w"}}}]
+			}}`,
+			settings: policy.Settings{CheckSelectors: true},
+			expectedViolations: []policy.Violation{
+				selectorViolation("civic", "spec.affinity.nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution[0].preference.matchExpressions[0].key"), //nolint:lll
+				selectorViolation("madam", "spec.affinity.podAffinity.requiredDuringSchedulingIgnoredDuringExecution[0].labelSelector.matchLabels.madam"),      //nolint:lll
+				selectorViolation("noon", "spec.topologySpreadConstraints[0].labelSelector.matchLabels.noon"),
+				selectorViolation("radar", "spec.nodeSelector.radar"),
+				selectorViolation("refer", "spec.affinity.podAntiAffinity.preferredDuringSchedulingIgnoredDuringExecution[0].podAffinityTerm.namespaceSelector.matchLabels.refer"), //nolint:lll
+				selectorViolation("rotor", "spec.affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms[0].matchExpressions[0].key"),               //nolint:lll
+			},
+		},
+		{
+			name:     "deployment pod template node selector",
+			kind:     "Deployment",
+			object:   `{"spec": {"template": {"spec": {"nodeSelector": {"radar": "x"}}}}}`,
+			settings: policy.Settings{CheckSelectors: true},
+			expectedViolations: []policy.Violation{
+				selectorViolation("radar", "spec.template.spec.nodeSelector.radar"),
+			},
+		},
+		{
+			name:   "selector keys allowed and denied like the labels",
+			kind:   "Service",
+			object: `{"spec": {"selector": {"level": "a", "owner": "b", "radar": "c"}}}`,
+			settings: policy.Settings{
+				CheckSelectors:     true,
+				AllowedPalindromes: []string{"level"},
+				DeniedLabelKeys:    []string{"owner"},
+			},
+			expectedViolations: []policy.Violation{
+				{Key: "owner", Path: "spec.selector.owner", Source: policy.SourceSelector, Rule: policy.RuleDeniedLabelKey},
+				selectorViolation("radar", "spec.selector.radar"),
+			},
+		},
+		{
+			name:     "selectors not checked by default",
+			kind:     "Service",
+			object:   `{"spec": {"selector": {"level": "a"}}}`,
+			settings: policy.Settings{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: tc.kind},
+				Object: []byte(tc.object),
+			}
+			settings := tc.settings
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestValidateSelectorsGrandfathersOldKeys(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:      kubewarden_protocol.GroupVersionKind{Kind: "PodDisruptionBudget"},
+		Operation: "UPDATE",
+		Object: []byte(`{"spec": {"selector": {
+			"matchLabels": {"level": "a", "radar": "b"},
+			"matchExpressions": [{"key": "kayak"}, {"key": "civic"}]
+		}}}`),
+		OldObject: []byte(`{"spec": {"selector": {
+			"matchLabels": {"level": "a"},
+			"matchExpressions": [{"key": "kayak"}]
+		}}}`),
+	}
+	settings := policy.Settings{CheckSelectors: true, NewViolationsOnly: true}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		selectorViolation("civic", "spec.selector.matchExpressions[1].key"),
+		selectorViolation("radar", "spec.selector.matchLabels.radar"),
+	}, violationsErr.Violations)
+	assert.Equal(
+		t,
+		"selector label key civic at spec.selector.matchExpressions[1].key not allowed, the word is a palindrome; "+
+			"selector label key radar at spec.selector.matchLabels.radar not allowed, the word is a palindrome",
+		violationsErr.Error(),
+	)
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
 	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
 )
 
@@ -16,8 +15,88 @@ func (e AllowedPalindromeError) Error() string {
 	return fmt.Sprintf("%s is not a palindrome, it could not be used as allowed palindrome", e.Field)
 }
 
+// ShortAllowedPalindromeError is returned for an allowed palindrome shorter
+// than the minimum length, that would never be rejected anyway.
+type ShortAllowedPalindromeError struct {
+	Field     string
+	MinLength int
+}
+
+func (e ShortAllowedPalindromeError) Error() string {
+	return fmt.Sprintf(
+		"%s is shorter than the minimum length %d, it could not be used as allowed palindrome",
+		e.Field,
+		e.MinLength,
+	)
+}
+
 type Settings struct {
-	AllowedPalindromes []string `json:"allowed_palindromes"`
+	AllowedPalindromes []string      `json:"allowed_palindromes"`
+	LabelKeyScope      LabelKeyScope `json:"label_key_scope,omitempty"`
+	DeniedLabelKeys    []string      `json:"denied_label_keys,omitempty"`
+	// Namespaces skipped by the policy, by exact name or pattern.
+	ExcludedNamespaces []string `json:"excluded_namespaces,omitempty"`
+	// Allowed palindromes added to the global ones in the matching namespaces.
+	NamespaceAllowedPalindromes map[string][]string `json:"namespace_allowed_palindromes,omitempty"`
+	// Identities whose requests are not validated, by exact name or pattern.
+	ExemptUsers           []string `json:"exempt_users,omitempty"`
+	ExemptGroups          []string `json:"exempt_groups,omitempty"`
+	ExemptServiceAccounts []string `json:"exempt_service_accounts,omitempty"`
+	// Reject on update only the keys not already present in the old object.
+	NewViolationsOnly bool `json:"new_violations_only,omitempty"`
+	// Fix the forbidden labels instead of rejecting the request.
+	Mutation *MutationSettings `json:"mutation,omitempty"`
+	// Validate the annotation keys too.
+	Annotations *AnnotationSettings `json:"annotations,omitempty"`
+	// Keys, by exact name or pattern, whose values must not be palindromes.
+	PalindromeValueKeys []string `json:"palindrome_value_keys,omitempty"`
+	// Purely numeric values, like 12321, are not checked.
+	IgnoreNumericValues bool `json:"ignore_numeric_values,omitempty"`
+	// Validate the object name and its generateName prefix too.
+	Names *NameSettings `json:"names,omitempty"`
+	// Validate the identifiers of the pod spec too.
+	PodSpec *PodSpecSettings `json:"pod_spec,omitempty"`
+	// Validate the label keys used by the selectors too.
+	CheckSelectors bool `json:"check_selectors,omitempty"`
+	// Fields selected by gjson paths validated too, for any kind.
+	Paths []PathRule `json:"paths,omitempty"`
+	// Validate the data keys of the ConfigMaps and of the Secrets too.
+	ConfigData *ConfigDataSettings `json:"config_data,omitempty"`
+	// How the words are compared, strict by default.
+	MatchMode MatchMode `json:"match_mode,omitempty"`
+	// Separators used by the match modes, "-_./" by default.
+	Separators string `json:"separators,omitempty"`
+	// Words shorter than this, once normalized, are never palindromes.
+	MinLength int `json:"min_length,omitempty"`
+	// Keys holding a palindrome at least this long are rejected too.
+	PalindromicSubstringLength int `json:"palindromic_substring_length,omitempty"`
+	// Keys at most this many edits away from a palindrome are rejected too.
+	MaxPalindromeDistance int `json:"max_palindrome_distance,omitempty"`
+	// The detectors flagging the palindrome keys, mirror by default.
+	PalindromeDetection PalindromeDetection `json:"palindrome_detection,omitempty"`
+	// Compare the skeleton of the words, without the invisible characters
+	// and with the confusable characters replaced by their prototype.
+	ConfusableSkeleton bool `json:"confusable_skeleton,omitempty"`
+	// Reject the keys holding bidirectional control characters.
+	RejectBidiControls bool `json:"reject_bidi_controls,omitempty"`
+	// Detectors, by name, flagging the palindromes of each field, in place
+	// of the palindrome check following the match mode.
+	Detectors map[Source][]string `json:"detectors,omitempty"`
+	// Reject the label keys that are the reverse of another label key or of
+	// their value, like stressed and desserts.
+	RejectReversePairs bool `json:"reject_reverse_pairs,omitempty"`
+
+	allowedPalindromePatterns Patterns
+	deniedLabelKeyPatterns    Patterns
+	excludedNamespacePatterns Patterns
+	exemptPatterns            map[ExemptionKind]Patterns
+
+	allowedAnnotationPalindromePatterns Patterns
+	palindromeValueKeyPatterns          Patterns
+	allowedNamePalindromePatterns       Patterns
+	allowedPodSpecPalindromePatterns    Patterns
+	configDataValueKeyPatterns          Patterns
+	resolvedDetectors                   map[Source][]ruleDetector
 }
 
 func NewSettingsFromValidationRequest(
@@ -34,22 +113,116 @@ func NewSettingsFromValidationRequest(
 	return &settings, nil
 }
 
-// Check if the allowed palindromes are really AllowedPalindromes.
+// Validate checks every setting, returning the first error found.
 func (s *Settings) Validate() error {
-	// Cannot use slices package functions, not supported by tinygo
-	for _, ap := range s.AllowedPalindromes {
-		if !word.IsPalindrome(ap) {
-			return AllowedPalindromeError{Field: ap}
+	validations := []func() error{
+		s.MatchMode.Validate,
+		s.PalindromeDetection.Validate,
+		s.validateMinLength,
+		s.validatePalindromicSubstringLength,
+		s.validateMaxPalindromeDistance,
+		func() error { return s.validateAllowedPalindromes(s.AllowedPalindromes, labelAllowlistSources()...) },
+		s.validateDeniedLabelKeys,
+		s.validateNamespaces,
+		s.validateExemptions,
+		s.validateSections,
+		s.validateSectionAllowedPalindromes,
+		s.validatePalindromeValueKeys,
+		s.validatePaths,
+		s.LabelKeyScope.Validate,
+		s.validateDetectors,
+	}
+	for _, validate := range validations {
+		if err := validate(); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (s *Settings) IsAnAllowedPalindrome(palindrome string) bool {
-	for _, ap := range s.AllowedPalindromes {
-		if ap == palindrome {
-			return true
+// validator is implemented by the optional sections of the settings.
+type validator interface {
+	Validate() error
+}
+
+// validateSections validates the optional sections that are set.
+func (s *Settings) validateSections() error {
+	var sections []validator
+	if s.Mutation != nil {
+		sections = append(sections, s.Mutation)
+	}
+	if s.Annotations != nil {
+		sections = append(sections, s.Annotations)
+	}
+	if s.Names != nil {
+		sections = append(sections, s.Names)
+	}
+	if s.PodSpec != nil {
+		sections = append(sections, s.PodSpec)
+	}
+	if s.ConfigData != nil {
+		sections = append(sections, s.ConfigData)
+	}
+
+	for _, section := range sections {
+		if err := section.Validate(); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// validateSectionAllowedPalindromes checks the allowed palindromes of the
+// optional sections that are set.
+func (s *Settings) validateSectionAllowedPalindromes() error {
+	if s.Annotations != nil {
+		if err := s.validateAllowedPalindromes(s.Annotations.AllowedPalindromes, SourceAnnotation); err != nil {
+			return err
+		}
+	}
+	if s.Names != nil {
+		if err := s.validateAllowedPalindromes(s.Names.AllowedPalindromes, SourceName); err != nil {
+			return err
+		}
+	}
+	if s.PodSpec != nil {
+		if err := s.validateAllowedPalindromes(s.PodSpec.AllowedPalindromes, podSpecSources()...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notAllowed allows no word, the allowed palindromes are checked against
+// the rules they exempt from.
+func notAllowed(string) bool {
 	return false
 }
+
+// validateAllowedPalindromes checks the allowed palindromes of the sources,
+// the literal ones must be flagged by one of the palindrome detectors of the
+// sources, following the match mode and the minimum length.
+func (s *Settings) validateAllowedPalindromes(allowedPalindromes []string, sources ...Source) error {
+	// Cannot use slices package functions, not supported by tinygo
+	for _, ap := range allowedPalindromes {
+		pattern, err := CompilePattern(ap)
+		if err != nil {
+			return err
+		}
+		if !pattern.IsLiteral() || s.isDetected(ap, sources, s.MinLength) {
+			continue
+		}
+		if s.isDetected(ap, sources, 0) {
+			return ShortAllowedPalindromeError{Field: ap, MinLength: s.MinLength}
+		}
+		return AllowedPalindromeError{Field: ap}
+	}
+	return nil
+}
+
+func (s *Settings) IsAnAllowedPalindrome(palindrome string) bool {
+	if s.allowedPalindromePatterns == nil {
+		s.allowedPalindromePatterns = compileValidPatterns(s.AllowedPalindromes)
+	}
+	return s.allowedPalindromePatterns.MatchAny(palindrome)
+}
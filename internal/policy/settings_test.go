@@ -72,10 +72,35 @@ func TestSettingsValidationErrors(t *testing.T) {
 			},
 			expectedError: policy.AllowedPalindromeError{Field: "carmine"},
 		},
+		{
+			name: "glob and regex patterns are not required to be palindromes",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"team-*-maet", `regex:.+\.example\.com/.+`, "aba"},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "invalid regex pattern not pass the validation",
+			settings: policy.Settings{
+				AllowedPalindromes: []string{"aba", "regex:a{1000}"},
+			},
+			expectedError: policy.InvalidPatternError{
+				Pattern: "regex:a{1000}",
+				Reason:  "repetitions above 100 are not allowed",
+			},
+		},
+		{
+			name: "unknown label key scope not pass the validation",
+			settings: policy.Settings{
+				LabelKeyScope: "domain",
+			},
+			expectedError: policy.InvalidLabelKeyScopeError{Scope: "domain"},
+		},
 		{
 			name: "palindrome validation without errors",
 			settings: policy.Settings{
 				AllowedPalindromes: []string{"aba", "level", "ebe"},
+				LabelKeyScope:      policy.LabelKeyScopePrefixLabels,
 			},
 			expectedError: nil,
 		},
@@ -105,4 +130,23 @@ func TestIsAnAllowedPalindrome(t *testing.T) {
 		found := settings.IsAnAllowedPalindrome("ebe")
 		assert.False(t, found)
 	})
+
+	t.Run("should return true if a palindrome matches an allowed pattern", func(t *testing.T) {
+		settings := policy.Settings{
+			AllowedPalindromes: []string{"team-*-maet", "regex:example\\.com/.+"},
+		}
+
+		assert.True(t, settings.IsAnAllowedPalindrome("team-abba-maet"))
+		assert.True(t, settings.IsAnAllowedPalindrome("example.com/aba"))
+		assert.False(t, settings.IsAnAllowedPalindrome("level"))
+	})
+
+	t.Run("should return true if the allowed palindrome is written in a different normal form", func(t *testing.T) {
+		settings := policy.Settings{
+			AllowedPalindromes: []string{"\u00e9t\u00e9"},
+		}
+
+		found := settings.IsAnAllowedPalindrome("e\u0301te\u0301")
+		assert.True(t, found)
+	})
 }
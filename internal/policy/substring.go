@@ -0,0 +1,26 @@
+package policy
+
+import "fmt"
+
+// minPalindromicSubstringLength is the shortest palindromic substring
+// worth rejecting: every character alone is a palindrome.
+const minPalindromicSubstringLength = 2
+
+type InvalidPalindromicSubstringLengthError struct {
+	Length int
+}
+
+func (e InvalidPalindromicSubstringLengthError) Error() string {
+	return fmt.Sprintf(
+		"%d is not a valid palindromic substring length, it must be at least %d",
+		e.Length,
+		minPalindromicSubstringLength,
+	)
+}
+
+func (s *Settings) validatePalindromicSubstringLength() error {
+	if s.PalindromicSubstringLength != 0 && s.PalindromicSubstringLength < minPalindromicSubstringLength {
+		return InvalidPalindromicSubstringLengthError{Length: s.PalindromicSubstringLength}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePalindromicSubstrings(t *testing.T) {
+	type testCase struct {
+		name               string
+		settings           policy.Settings
+		object             string
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "substrings not checked by default",
+			settings: policy.Settings{},
+			object:   `{"metadata": {"labels": {"team-racecar-prod": "a"}}}`,
+		},
+		{
+			name:     "label key holding a long palindrome",
+			settings: policy.Settings{PalindromicSubstringLength: 5},
+			object:   `{"metadata": {"labels": {"teamracecarprod": "a"}}}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "teamracecarprod",
+					Path:   "metadata.labels.teamracecarprod",
+					Source: policy.SourceLabel,
+					Rule:   policy.RulePalindromicSubstring,
+					Value:  "racecar",
+					Offset: 4,
+				},
+			},
+		},
+		{
+			name:     "label key holding a palindrome shorter than the threshold",
+			settings: policy.Settings{PalindromicSubstringLength: 8},
+			object:   `{"metadata": {"labels": {"teamracecarprod": "a"}}}`,
+		},
+		{
+			name:     "palindrome label key reported only once",
+			settings: policy.Settings{PalindromicSubstringLength: 3},
+			object:   `{"metadata": {"labels": {"level": "a"}}}`,
+			expectedViolations: []policy.Violation{
+				{Key: "level", Path: "metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+			},
+		},
+		{
+			name:     "allowed palindromic substring",
+			settings: policy.Settings{PalindromicSubstringLength: 5, AllowedPalindromes: []string{"racecar"}},
+			object:   `{"metadata": {"labels": {"teamracecarprod": "a"}}}`,
+		},
+		{
+			name:     "allowed label key",
+			settings: policy.Settings{PalindromicSubstringLength: 5, AllowedPalindromes: []string{"team*"}},
+			object:   `{"metadata": {"labels": {"teamracecarprod": "a"}}}`,
+		},
+		{
+			name: "annotation key holding a long palindrome",
+			settings: policy.Settings{
+				PalindromicSubstringLength: 4,
+				Annotations:                &policy.AnnotationSettings{},
+			},
+			object: `{"metadata": {"annotations": {"example.com/noon-x": "a"}}}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "example.com/noon-x",
+					Path:   `metadata.annotations["example.com/noon-x"]`,
+					Source: policy.SourceAnnotation,
+					Rule:   policy.RulePalindromicSubstring,
+					Value:  "noon",
+					Offset: 12,
+				},
+			},
+		},
+		{
+			name:     "palindrome in the prefix of a key checked by name",
+			settings: policy.Settings{PalindromicSubstringLength: 4, LabelKeyScope: policy.LabelKeyScopeName},
+			object:   `{"metadata": {"labels": {"abba.example.com/foo": "a"}}}`,
+		},
+		{
+			name:     "palindrome in the name of a key checked by name",
+			settings: policy.Settings{PalindromicSubstringLength: 4, LabelKeyScope: policy.LabelKeyScopeName},
+			object:   `{"metadata": {"labels": {"example.com/xnoonx-a": "a"}}}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "example.com/xnoonx-a",
+					Path:   `metadata.labels["example.com/xnoonx-a"]`,
+					Source: policy.SourceLabel,
+					Rule:   policy.RulePalindromicSubstring,
+					Value:  "xnoonx",
+					Offset: 0,
+				},
+			},
+		},
+		{
+			name: "palindrome across the separators ignored by the match mode",
+			settings: policy.Settings{
+				PalindromicSubstringLength: 5,
+				MatchMode:                  policy.MatchIgnoreSeparators,
+			},
+			object: `{"metadata": {"labels": {"team-ra-ce-car": "a"}}}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "team-ra-ce-car",
+					Path:   "metadata.labels.team-ra-ce-car",
+					Source: policy.SourceLabel,
+					Rule:   policy.RulePalindromicSubstring,
+					Value:  "racecar",
+					Offset: 4,
+				},
+			},
+		},
+		{
+			name: "ignored annotation key",
+			settings: policy.Settings{
+				PalindromicSubstringLength: 4,
+				Annotations:                &policy.AnnotationSettings{IgnoredPrefixes: []string{"example.com/"}},
+			},
+			object: `{"metadata": {"annotations": {"example.com/noon-x": "a"}}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+				Object: []byte(tc.object),
+			}
+			settings := tc.settings
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
+
+func TestPalindromicSubstringViolationMessage(t *testing.T) {
+	err := policy.ViolationsError{
+		Violations: []policy.Violation{
+			{
+				Key:    "teamracecarprod",
+				Path:   "metadata.labels.teamracecarprod",
+				Source: policy.SourceLabel,
+				Rule:   policy.RulePalindromicSubstring,
+				Value:  "racecar",
+				Offset: 4,
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		`label with key teamracecarprod at metadata.labels.teamracecarprod not allowed, the substring "racecar" at offset 4 is a palindrome`, //nolint:lll
+		err.Error(),
+	)
+}
+
+func TestPalindromicSubstringCannotBeRenamed(t *testing.T) {
+	response := validateRawObject(t, "Pod", `{"metadata": {"labels": {"teamracecarprod": "a"}}}`, policy.Settings{
+		PalindromicSubstringLength: 5,
+		Mutation:                   &policy.MutationSettings{Strategy: policy.MutationRename, Prefix: "x-"},
+	})
+
+	assert.False(t, response.Accepted)
+	assert.Contains(t, *response.Message, `renaming the key does not remove the palindromic substring "racecar"`)
+}
+
+func TestPalindromicSubstringLengthValidation(t *testing.T) {
+	for _, length := range []int{0, 2, 7} {
+		settings := policy.Settings{PalindromicSubstringLength: length}
+		require.NoError(t, settings.Validate())
+	}
+
+	for _, length := range []int{-1, 1} {
+		settings := policy.Settings{PalindromicSubstringLength: length}
+		require.ErrorIs(t, settings.Validate(), policy.InvalidPalindromicSubstringLengthError{Length: length})
+	}
+}
+
+func TestAllowedPalindromicSubstringsValidation(t *testing.T) {
+	settings := policy.Settings{PalindromicSubstringLength: 5, AllowedPalindromes: []string{"teamracecarprod"}}
+	require.NoError(t, settings.Validate())
+	response := validateRawObject(t, "Pod", `{"metadata": {"labels": {"teamracecarprod": "a"}}}`, settings)
+	assert.True(t, response.Accepted)
+
+	settings = policy.Settings{PalindromicSubstringLength: 8, AllowedPalindromes: []string{"teamracecarprod"}}
+	require.ErrorIs(t, settings.Validate(), policy.AllowedPalindromeError{Field: "teamracecarprod"})
+}
@@ -2,10 +2,10 @@ package policy
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
-	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
 	"github.com/francoispqt/onelog"
 	kubewarden "github.com/kubewarden/policy-sdk-go"
 	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
@@ -13,7 +13,104 @@ import (
 	"github.com/wapc/wapc-guest-tinygo"
 )
 
-const httpBadRequestStatusCode = 400
+const (
+	httpBadRequestStatusCode = 400
+
+	operationUpdate = "UPDATE"
+	operationDelete = "DELETE"
+)
+
+// ValidateLabels returns a ViolationsError holding everything refused by the
+// settings of the request namespace in the admitted object.
+func ValidateLabels(request *kubewarden_protocol.KubernetesAdmissionRequest, settings *Settings) error {
+	if request.Operation == operationDelete || settings.IsAnExcludedNamespace(request.Namespace) {
+		return nil
+	}
+	settings = settings.ForNamespace(request.Namespace)
+	object := gjson.ParseBytes(request.Object)
+	oldObject := gjson.ParseBytes(request.OldObject)
+	grandfatherOldKeys := settings.NewViolationsOnly && request.Operation == operationUpdate
+
+	keys := keysScanner{settings: settings, object: object, oldObject: oldObject, grandfatherOldKeys: grandfatherOldKeys}
+	for _, path := range labelsPaths(request.Kind.Kind) {
+		keys.scan(path, SourceLabel)
+	}
+	if settings.Annotations != nil {
+		for _, path := range annotationsPaths(request.Kind.Kind) {
+			keys.scan(path, SourceAnnotation)
+		}
+	}
+
+	violations := keys.violations
+	if settings.Names != nil {
+		violations = append(violations, settings.nameViolations(object, oldObject, grandfatherOldKeys)...)
+	}
+	kindScanners := []func(kind string, object, oldObject gjson.Result, grandfatherOldKeys bool) []Violation{
+		settings.podSpecViolations,
+		settings.selectorViolations,
+		settings.pathViolations,
+		settings.configDataViolations,
+		settings.reversePairViolations,
+	}
+	for _, scan := range kindScanners {
+		violations = append(violations, scan(request.Kind.Kind, object, oldObject, grandfatherOldKeys)...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	sortViolations(violations)
+	return ViolationsError{Violations: violations}
+}
+
+// keysScanner collects the violations of the keys, and of their values, of
+// the maps of the admitted object.
+type keysScanner struct {
+	settings           *Settings
+	object             gjson.Result
+	oldObject          gjson.Result
+	grandfatherOldKeys bool
+	violations         []Violation
+}
+
+// scan checks every key of the map found at the path.
+func (k *keysScanner) scan(path string, source Source) {
+	k.object.Get(path).ForEach(func(key, value gjson.Result) bool {
+		k.check(path, key.String(), value.String(), source)
+		return true
+	})
+}
+
+func (k *keysScanner) check(path, key, value string, source Source) {
+	keyPath := jsonPath(path, key)
+	oldValue := k.oldObject.Get(gjsonPath(path, key))
+
+	if !k.grandfatherOldKeys || !oldValue.Exists() {
+		k.violations = append(k.violations, k.settings.keyViolations(key, keyPath, source)...)
+	}
+
+	// an unchanged value is grandfathered even when its key is not
+	grandfatheredValue := k.grandfatherOldKeys && oldValue.Exists() && oldValue.String() == value
+	if !grandfatheredValue && k.settings.IsForbiddenValue(key, value) {
+		k.violations = append(k.violations, Violation{
+			Key:    key,
+			Path:   keyPath,
+			Source: source,
+			Rule:   RulePalindromeValue,
+			Value:  value,
+		})
+	}
+}
+
+// keyViolations returns the violations of the key found at the path in a
+// map of the given source.
+func (s *Settings) keyViolations(key, path string, source Source) []Violation {
+	if source == SourceAnnotation && !s.isCheckedAnnotationKey(key) {
+		return nil
+	}
+	return s.detectorViolations(key, Violation{Key: key, Path: path, Source: source})
+}
 
 func NewValidate(logger *onelog.Logger) wapc.Function {
 	ctxLogger := logger.With(func(e onelog.Entry) {
@@ -31,6 +128,10 @@ func NewValidate(logger *onelog.Logger) wapc.Function {
 				kubewarden.Code(httpBadRequestStatusCode))
 		}
 
+		if validationRequest.Request.Operation == operationDelete {
+			return kubewarden.AcceptRequest()
+		}
+
 		settings, err := NewSettingsFromValidationRequest(&validationRequest)
 		if err != nil {
 			ctxLogger.ErrorWithFields("could not create settings from validation request", func(e onelog.Entry) {
@@ -41,32 +142,40 @@ func NewValidate(logger *onelog.Logger) wapc.Function {
 				kubewarden.Code(httpBadRequestStatusCode))
 		}
 
-		podMetadata := gjson.GetBytes(
-			validationRequest.Request.Object,
-			"metadata",
-		)
-
-		rawPodLabels := podMetadata.Get("labels")
-		podName := podMetadata.Get("name").String()
-
-		var invalidLabelErr error
-		rawPodLabels.ForEach(func(key, _ gjson.Result) bool {
-			labelKey := key.String()
+		objectName := gjson.GetBytes(validationRequest.Request.Object, "metadata.name").String()
 
-			if word.IsPalindrome(labelKey) && !settings.IsAnAllowedPalindrome(labelKey) {
-				invalidLabelErr = fmt.Errorf("pod label with key %s not allowed, the word is a palindrome", labelKey)
-			}
+		if settings.IsAnExcludedNamespace(validationRequest.Request.Namespace) {
+			ctxLogger.DebugWithFields("namespace excluded, skipping validation", func(e onelog.Entry) {
+				e.String("object_name", objectName)
+				e.String("namespace", validationRequest.Request.Namespace)
+			})
+			return kubewarden.AcceptRequest()
+		}
 
-			return invalidLabelErr == nil
-		})
+		if exemption, exempted := settings.Exemption(validationRequest.Request.UserInfo); exempted {
+			ctxLogger.InfoWithFields("request exempted, skipping validation", func(e onelog.Entry) {
+				e.String("object_name", objectName)
+				e.String("username", validationRequest.Request.UserInfo.Username)
+				e.String("exempted_by", string(exemption.Kind))
+				e.String("exempted_identity", exemption.Identity)
+			})
+			return kubewarden.AcceptRequest()
+		}
 
-		if invalidLabelErr != nil {
-			ctxLogger.InfoWithFields("could not validate pod, palindrome label keys found", func(e onelog.Entry) {
-				e.String("pod_name", podName)
+		err = ValidateLabels(&validationRequest.Request, settings)
+		var violationsErr ViolationsError
+		if errors.As(err, &violationsErr) && settings.Mutation != nil {
+			return mutateRequest(ctxLogger, &validationRequest.Request, violationsErr, settings)
+		}
+		if err != nil {
+			ctxLogger.InfoWithFields("could not validate object, forbidden label keys found", func(e onelog.Entry) {
+				e.String("object_name", objectName)
+				e.String("object_kind", validationRequest.Request.Kind.Kind)
 				e.String("allowed_palindromes", strings.Join(settings.AllowedPalindromes, ","))
+				e.String("violations", strings.Join(violationsErr.Keys(), ","))
 			})
 			return kubewarden.RejectRequest(
-				kubewarden.Message(invalidLabelErr.Error()),
+				kubewarden.Message(err.Error()),
 				kubewarden.NoCode,
 			)
 		}
@@ -75,6 +184,39 @@ func NewValidate(logger *onelog.Logger) wapc.Function {
 	}
 }
 
+// mutateRequest fixes the violations of the request following the mutation
+// settings, rejecting the request when they cannot be fixed.
+func mutateRequest(
+	ctxLogger *onelog.Logger,
+	request *kubewarden_protocol.KubernetesAdmissionRequest,
+	violationsErr ViolationsError,
+	settings *Settings,
+) ([]byte, error) {
+	objectName := gjson.GetBytes(request.Object, "metadata.name").String()
+
+	mutatedObject, err := MutateLabels(request, violationsErr.Violations, settings)
+	if err != nil {
+		ctxLogger.InfoWithFields("could not mutate object, forbidden label keys found", func(e onelog.Entry) {
+			e.String("object_name", objectName)
+			e.String("object_kind", request.Kind.Kind)
+			e.String("violations", strings.Join(violationsErr.Keys(), ","))
+			e.Err("error", err)
+		})
+		return kubewarden.RejectRequest(
+			kubewarden.Message(fmt.Sprintf("%s; %s", violationsErr.Error(), err.Error())),
+			kubewarden.NoCode,
+		)
+	}
+
+	ctxLogger.InfoWithFields("object mutated, forbidden label keys fixed", func(e onelog.Entry) {
+		e.String("object_name", objectName)
+		e.String("object_kind", request.Kind.Kind)
+		e.String("strategy", string(settings.Mutation.Strategy))
+		e.String("violations", strings.Join(violationsErr.Keys(), ","))
+	})
+	return kubewarden.MutateRequest(mutatedObject)
+}
+
 func NewValidateSettings(logger *onelog.Logger) wapc.Function {
 	ctxLogger := logger.With(func(e onelog.Entry) {
 		e.String("context", "validate_settings")
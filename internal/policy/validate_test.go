@@ -6,6 +6,8 @@ import (
 
 	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
 	"github.com/francoispqt/onelog"
+	appsv1 "github.com/kubewarden/k8s-objects/api/apps/v1"
+	batchv1 "github.com/kubewarden/k8s-objects/api/batch/v1"
 	corev1 "github.com/kubewarden/k8s-objects/api/core/v1"
 	metav1 "github.com/kubewarden/k8s-objects/apimachinery/pkg/apis/meta/v1"
 	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
@@ -144,7 +146,7 @@ func TestValidatePodLabelsError(t *testing.T) {
 		{
 			name:                 "should return error when settings are empty and there is a label key palindrome",
 			settings:             policy.Settings{},
-			expectedErrorContent: "pod label with key level not allowed, the word is a palindrome",
+			expectedErrorContent: "label with key level at metadata.labels.level not allowed, the word is a palindrome",
 			pod: corev1.Pod{
 				Metadata: &metav1.ObjectMeta{
 					Name:      "test-pod",
@@ -160,7 +162,7 @@ func TestValidatePodLabelsError(t *testing.T) {
 			settings: policy.Settings{
 				AllowedPalindromes: []string{"aba"},
 			},
-			expectedErrorContent: "pod label with key level not allowed, the word is a palindrome",
+			expectedErrorContent: "label with key level at metadata.labels.level not allowed, the word is a palindrome",
 			pod: corev1.Pod{
 				Metadata: &metav1.ObjectMeta{
 					Name:      "test-pod",
@@ -171,6 +173,22 @@ func TestValidatePodLabelsError(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should return error when there is a denied label key, even if it is not a palindrome",
+			settings: policy.Settings{
+				DeniedLabelKeys: []string{"owner"},
+			},
+			expectedErrorContent: "label with key owner at metadata.labels.owner not allowed, the key is denied by the denied_label_keys setting", //nolint:lll
+			pod: corev1.Pod{
+				Metadata: &metav1.ObjectMeta{
+					Name:      "test-pod",
+					Namespace: "default",
+					Labels: map[string]string{
+						"owner": "team-a",
+					},
+				},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			var response kubewarden_protocol.ValidationResponse
@@ -186,3 +204,287 @@ func TestValidatePodLabelsError(t *testing.T) {
 		})
 	}
 }
+
+func buildValidationRequestWithKind(t *testing.T, kind string, object, settings interface{}) []byte {
+	t.Helper()
+
+	objectRaw, err := json.Marshal(object)
+	require.NoError(t, err)
+	settingsRaw, err := json.Marshal(settings)
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(kubewarden_protocol.ValidationRequest{
+		Request: kubewarden_protocol.KubernetesAdmissionRequest{
+			Kind:   kubewarden_protocol.GroupVersionKind{Kind: kind},
+			Object: objectRaw,
+		},
+		Settings: settingsRaw,
+	})
+	require.NoError(t, err)
+
+	return payload
+}
+
+func podTemplate(labels map[string]string) *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		Metadata: &metav1.ObjectMeta{
+			Labels: labels,
+		},
+	}
+}
+
+func TestValidateWorkloads(t *testing.T) {
+	validate := policy.NewValidate(&onelog.Logger{})
+
+	type testCase struct {
+		name                 string
+		kind                 string
+		object               interface{}
+		expectedErrorContent string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name: "should accept a deployment without palindrome labels",
+			kind: "Deployment",
+			object: appsv1.Deployment{
+				Metadata: &metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}},
+				Spec:     &appsv1.DeploymentSpec{Template: podTemplate(map[string]string{"app": "web"})},
+			},
+		},
+		{
+			name: "should reject a deployment with a palindrome label in the pod template",
+			kind: "Deployment",
+			object: appsv1.Deployment{
+				Metadata: &metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}},
+				Spec:     &appsv1.DeploymentSpec{Template: podTemplate(map[string]string{"level": "debug"})},
+			},
+			expectedErrorContent: "label with key level at spec.template.metadata.labels.level not allowed, the word is a palindrome", //nolint:lll
+		},
+		{
+			name: "should reject a statefulset with a palindrome label on the workload",
+			kind: "StatefulSet",
+			object: appsv1.StatefulSet{
+				Metadata: &metav1.ObjectMeta{Name: "db", Labels: map[string]string{"aba": "yes"}},
+				Spec:     &appsv1.StatefulSetSpec{Template: podTemplate(map[string]string{"app": "db"})},
+			},
+			expectedErrorContent: "label with key aba at metadata.labels.aba not allowed, the word is a palindrome",
+		},
+		{
+			name: "should reject a daemonset with a palindrome label in the pod template",
+			kind: "DaemonSet",
+			object: appsv1.DaemonSet{
+				Metadata: &metav1.ObjectMeta{Name: "agent"},
+				Spec:     &appsv1.DaemonSetSpec{Template: podTemplate(map[string]string{"civic": "yes"})},
+			},
+			expectedErrorContent: "label with key civic at spec.template.metadata.labels.civic not allowed, the word is a palindrome", //nolint:lll
+		},
+		{
+			name: "should reject a job with a palindrome label in the pod template",
+			kind: "Job",
+			object: batchv1.Job{
+				Metadata: &metav1.ObjectMeta{Name: "migrate"},
+				Spec:     &batchv1.JobSpec{Template: podTemplate(map[string]string{"kayak": "yes"})},
+			},
+			expectedErrorContent: "label with key kayak at spec.template.metadata.labels.kayak not allowed, the word is a palindrome", //nolint:lll
+		},
+		{
+			name: "should reject a cronjob with a palindrome label in the job pod template",
+			kind: "CronJob",
+			object: batchv1.CronJob{
+				Metadata: &metav1.ObjectMeta{Name: "backup"},
+				Spec: &batchv1.CronJobSpec{
+					JobTemplate: &batchv1.JobTemplateSpec{
+						Spec: &batchv1.JobSpec{Template: podTemplate(map[string]string{"level": "debug"})},
+					},
+				},
+			},
+			expectedErrorContent: "label with key level at spec.jobTemplate.spec.template.metadata.labels.level not allowed, the word is a palindrome", //nolint:lll
+		},
+		{
+			name: "should reject a cronjob with a palindrome label on the created jobs",
+			kind: "CronJob",
+			object: batchv1.CronJob{
+				Metadata: &metav1.ObjectMeta{Name: "backup"},
+				Spec: &batchv1.CronJobSpec{
+					JobTemplate: &batchv1.JobTemplateSpec{
+						Metadata: &metav1.ObjectMeta{Labels: map[string]string{"abba": "yes"}},
+						Spec:     &batchv1.JobSpec{Template: podTemplate(map[string]string{"app": "backup"})},
+					},
+				},
+			},
+			expectedErrorContent: "label with key abba at spec.jobTemplate.metadata.labels.abba not allowed, the word is a palindrome", //nolint:lll
+		},
+		{
+			name: "should reject a replicationcontroller with a palindrome label in the pod template",
+			kind: "ReplicationController",
+			object: corev1.ReplicationController{
+				Metadata: &metav1.ObjectMeta{Name: "legacy"},
+				Spec:     &corev1.ReplicationControllerSpec{Template: podTemplate(map[string]string{"radar": "on"})},
+			},
+			expectedErrorContent: "label with key radar at spec.template.metadata.labels.radar not allowed, the word is a palindrome", //nolint:lll
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var response kubewarden_protocol.ValidationResponse
+			payload := buildValidationRequestWithKind(t, tc.kind, tc.object, policy.Settings{})
+			result, err := validate(payload)
+			require.NoError(t, err)
+			err = json.Unmarshal(result, &response)
+			require.NoError(t, err)
+
+			if tc.expectedErrorContent == "" {
+				assert.True(t, response.Accepted)
+				return
+			}
+			assert.False(t, response.Accepted)
+			assert.Contains(t, *response.Message, tc.expectedErrorContent)
+		})
+	}
+}
+
+func TestValidateUpdates(t *testing.T) {
+	oldObject := []byte(`{"metadata": {"labels": {"level": "1", "team": "a"}}}`)
+	newObject := []byte(`{"metadata": {"labels": {"level": "2", "aba": "b", "team": "a"}}}`)
+
+	type testCase struct {
+		name                  string
+		operation             string
+		settings              policy.Settings
+		expectedViolatingKeys []string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:                  "every palindrome key is rejected on update by default",
+			operation:             "UPDATE",
+			settings:              policy.Settings{},
+			expectedViolatingKeys: []string{"aba", "level"},
+		},
+		{
+			name:                  "only the palindrome keys added by the update are rejected in new violations only mode",
+			operation:             "UPDATE",
+			settings:              policy.Settings{NewViolationsOnly: true},
+			expectedViolatingKeys: []string{"aba"},
+		},
+		{
+			name:                  "every palindrome key is rejected on create in new violations only mode",
+			operation:             "CREATE",
+			settings:              policy.Settings{NewViolationsOnly: true},
+			expectedViolatingKeys: []string{"aba", "level"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Operation: tc.operation,
+				Object:    newObject,
+				OldObject: oldObject,
+			}
+
+			err := policy.ValidateLabels(&request, &tc.settings)
+
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolatingKeys, violationsErr.Keys())
+		})
+	}
+
+	t.Run("update not adding palindrome keys is accepted in new violations only mode", func(t *testing.T) {
+		request := kubewarden_protocol.KubernetesAdmissionRequest{
+			Operation: "UPDATE",
+			Object:    []byte(`{"metadata": {"labels": {"level": "1", "team": "b"}}}`),
+			OldObject: oldObject,
+		}
+
+		err := policy.ValidateLabels(&request, &policy.Settings{NewViolationsOnly: true})
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidateDeleteIsAccepted(t *testing.T) {
+	validate := policy.NewValidate(&onelog.Logger{})
+	payload, err := json.Marshal(kubewarden_protocol.ValidationRequest{
+		Request: kubewarden_protocol.KubernetesAdmissionRequest{
+			Operation: "DELETE",
+			OldObject: []byte(`{"metadata": {"labels": {"level": "1"}}}`),
+		},
+		Settings: []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	var response kubewarden_protocol.ValidationResponse
+	result, err := validate(payload)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(result, &response))
+	assert.True(t, response.Accepted)
+}
+
+func TestValidateBidiControls(t *testing.T) {
+	type testCase struct {
+		name               string
+		settings           policy.Settings
+		object             string
+		expectedViolations []policy.Violation
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:     "bidi controls accepted by default",
+			settings: policy.Settings{},
+			object:   `{"metadata": {"labels": {"team\u202e": "a"}}}`,
+		},
+		{
+			name:     "label key holding a bidi override",
+			settings: policy.Settings{RejectBidiControls: true},
+			object:   `{"metadata": {"labels": {"team\u202e": "a"}}}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "team\u202e",
+					Path:   `metadata.labels["team\u202e"]`,
+					Source: policy.SourceLabel,
+					Rule:   policy.RuleBidiControl,
+				},
+			},
+		},
+		{
+			name: "allowed palindrome holding a bidi isolate",
+			settings: policy.Settings{
+				RejectBidiControls: true,
+				AllowedPalindromes: []string{"regex:.*"},
+				Annotations:        &policy.AnnotationSettings{},
+			},
+			object: `{"metadata": {"annotations": {"a\u2066b\u2069": "a"}}}`,
+			expectedViolations: []policy.Violation{
+				{
+					Key:    "a\u2066b\u2069",
+					Path:   `metadata.annotations["a\u2066b\u2069"]`,
+					Source: policy.SourceAnnotation,
+					Rule:   policy.RuleBidiControl,
+				},
+			},
+		},
+		{
+			name:     "label key without bidi controls",
+			settings: policy.Settings{RejectBidiControls: true},
+			object:   `{"metadata": {"labels": {"le\u200bvel": "a"}}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			request := kubewarden_protocol.KubernetesAdmissionRequest{
+				Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+				Object: []byte(tc.object),
+			}
+			settings := tc.settings
+
+			err := policy.ValidateLabels(&request, &settings)
+
+			if tc.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var violationsErr policy.ViolationsError
+			require.ErrorAs(t, err, &violationsErr)
+			assert.Equal(t, tc.expectedViolations, violationsErr.Violations)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package policy
+
+import (
+	"unicode"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+)
+
+// isNumeric reports whether the value is made only of digits.
+func isNumeric(value string) bool {
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return value != ""
+}
+
+func (s *Settings) validatePalindromeValueKeys() error {
+	_, err := CompilePatterns(s.PalindromeValueKeys)
+	return err
+}
+
+// IsForbiddenValue reports whether the value of the key must not be a
+// palindrome and the strict detector finds one, following the match mode
+// and the minimum length. Empty values are never forbidden, the numeric ones
+// are ignored when requested by the settings.
+func (s *Settings) IsForbiddenValue(key, value string) bool {
+	if value == "" || (s.IgnoreNumericValues && isNumeric(value)) {
+		return false
+	}
+	if s.palindromeValueKeyPatterns == nil {
+		s.palindromeValueKeyPatterns = compileValidPatterns(s.PalindromeValueKeys)
+	}
+	if !s.palindromeValueKeyPatterns.MatchAny(key) {
+		return false
+	}
+	strict := newRuleDetector(word.StrictDetector{}, false)
+	_, found := s.detect(value, false, notAllowed, s.MinLength, strict)
+	return found
+}
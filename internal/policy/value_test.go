@@ -0,0 +1,198 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsForbiddenValue(t *testing.T) {
+	type testCase struct {
+		name      string
+		settings  policy.Settings
+		key       string
+		value     string
+		forbidden bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:      "values not checked by default",
+			settings:  policy.Settings{},
+			key:       "env",
+			value:     "racecar",
+			forbidden: false,
+		},
+		{
+			name:      "palindrome value of a configured key",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"env", "tier"}},
+			key:       "env",
+			value:     "racecar",
+			forbidden: true,
+		},
+		{
+			name:      "palindrome value of a key matching a configured pattern",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"example.com/*"}},
+			key:       "example.com/tier",
+			value:     "Level",
+			forbidden: true,
+		},
+		{
+			name:      "palindrome value of a key not configured",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"env"}},
+			key:       "app",
+			value:     "racecar",
+			forbidden: false,
+		},
+		{
+			name:      "not palindrome value",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"env"}},
+			key:       "env",
+			value:     "production",
+			forbidden: false,
+		},
+		{
+			name:      "empty value",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"env"}},
+			key:       "env",
+			value:     "",
+			forbidden: false,
+		},
+		{
+			name:      "numeric palindrome value",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"env"}},
+			key:       "env",
+			value:     "12321",
+			forbidden: true,
+		},
+		{
+			name:      "numeric palindrome value ignored",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"env"}, IgnoreNumericValues: true},
+			key:       "env",
+			value:     "12321",
+			forbidden: false,
+		},
+		{
+			name:      "alphanumeric palindrome value not ignored as numeric",
+			settings:  policy.Settings{PalindromeValueKeys: []string{"env"}, IgnoreNumericValues: true},
+			key:       "env",
+			value:     "1a1",
+			forbidden: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			assert.Equal(t, tc.forbidden, settings.IsForbiddenValue(tc.key, tc.value))
+		})
+	}
+}
+
+func TestValidateLabelsReportsPalindromeValues(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind: kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+		Object: []byte(`{
+			"metadata": {
+				"labels": {"env": "racecar", "tier": "12321", "app": "kayak", "level": "prod"},
+				"annotations": {"env": "refer"}
+			}
+		}`),
+	}
+	settings := policy.Settings{
+		PalindromeValueKeys: []string{"env", "tier", "level"},
+		IgnoreNumericValues: true,
+		Annotations:         &policy.AnnotationSettings{},
+	}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{
+			Key:    "env",
+			Path:   "metadata.annotations.env",
+			Source: policy.SourceAnnotation,
+			Rule:   policy.RulePalindromeValue,
+			Value:  "refer",
+		},
+		{
+			Key:    "env",
+			Path:   "metadata.labels.env",
+			Source: policy.SourceLabel,
+			Rule:   policy.RulePalindromeValue,
+			Value:  "racecar",
+		},
+		{Key: "level", Path: "metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+	}, violationsErr.Violations)
+	assert.Equal(
+		t,
+		"annotation with key env at metadata.annotations.env not allowed, the value refer is a palindrome; "+
+			"label with key env at metadata.labels.env not allowed, the value racecar is a palindrome; "+
+			"label with key level at metadata.labels.level not allowed, the word is a palindrome",
+		violationsErr.Error(),
+	)
+}
+
+func TestValidateLabelsGrandfathersUnchangedValues(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:      kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+		Operation: "UPDATE",
+		Object:    []byte(`{"metadata": {"labels": {"env": "racecar", "tier": "kayak"}}}`),
+		OldObject: []byte(`{"metadata": {"labels": {"env": "racecar", "tier": "backend"}}}`),
+	}
+	settings := policy.Settings{
+		PalindromeValueKeys: []string{"env", "tier"},
+		NewViolationsOnly:   true,
+	}
+
+	err := policy.ValidateLabels(&request, &settings)
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{
+			Key:    "tier",
+			Path:   "metadata.labels.tier",
+			Source: policy.SourceLabel,
+			Rule:   policy.RulePalindromeValue,
+			Value:  "kayak",
+		},
+	}, violationsErr.Violations)
+}
+
+func TestMutatePalindromeValues(t *testing.T) {
+	object := `{"metadata": {"labels": {"env": "racecar", "team": "a"}}}`
+	settings := policy.Settings{
+		PalindromeValueKeys: []string{"env"},
+		Mutation:            &policy.MutationSettings{Strategy: policy.MutationDrop},
+	}
+
+	response := validateRawObject(t, "Pod", object, settings)
+	require.True(t, response.Accepted)
+	assert.Equal(
+		t,
+		map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"team": "a"}}},
+		response.MutatedObject,
+	)
+
+	settings.Mutation = &policy.MutationSettings{Strategy: policy.MutationRename, Prefix: "old-"}
+	response = validateRawObject(t, "Pod", object, settings)
+	assert.False(t, response.Accepted)
+	assert.Contains(
+		t,
+		*response.Message,
+		"label with key env at metadata.labels.env cannot be mutated: "+
+			"renaming the key does not fix the palindrome value racecar",
+	)
+}
+
+func TestPalindromeValueKeysValidation(t *testing.T) {
+	settings := policy.Settings{PalindromeValueKeys: []string{"regex:[a-"}}
+
+	var invalidPatternErr policy.InvalidPatternError
+	require.ErrorAs(t, settings.Validate(), &invalidPatternErr)
+	assert.Equal(t, "regex:[a-", invalidPatternErr.Pattern)
+}
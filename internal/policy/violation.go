@@ -0,0 +1,219 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule is the check of the policy refusing a key.
+type Rule string
+
+const (
+	RulePalindrome     Rule = "palindrome"
+	RuleDeniedLabelKey Rule = "denied_label_keys"
+	// RulePalindromeValue refuses the value of the key, not the key itself.
+	RulePalindromeValue Rule = "palindrome_value"
+	// RulePalindromicSubstring refuses the key holding a long palindrome.
+	RulePalindromicSubstring Rule = "palindromic_substring"
+	// RuleBidiControl refuses the key holding bidirectional controls.
+	RuleBidiControl Rule = "bidi_control"
+	// RuleNearPalindrome refuses the key a few edits away from a palindrome.
+	RuleNearPalindrome Rule = "near_palindrome"
+	// RulePermutationPalindrome refuses the key whose characters can be
+	// rearranged in a palindrome, found by the permutation detector.
+	RulePermutationPalindrome Rule = "permutation_palindrome"
+	// RuleReversePair refuses the label key that is the reverse of another
+	// label key of the same map.
+	RuleReversePair Rule = "reverse_pair"
+	// RuleReverseValue refuses the label key that is the reverse of its value.
+	RuleReverseValue Rule = "reverse_value"
+)
+
+// Source is the kind of map holding a refused key, the name for the
+// violations of the object name or the kind of pod spec identifier.
+type Source string
+
+const (
+	SourceLabel      Source = "label"
+	SourceAnnotation Source = "annotation"
+	SourceName       Source = "name"
+	// Label keys used by the selectors.
+	SourceSelector Source = "selector"
+	// Keys and values selected by the path rules.
+	SourcePathKey   Source = "path_key"
+	SourcePathValue Source = "path_value"
+	// Keys of the data of the ConfigMaps and of the Secrets.
+	SourceDataKey Source = "data_key"
+	// Identifiers of the pod spec.
+	SourceContainer          Source = "container"
+	SourceInitContainer      Source = "init_container"
+	SourceEphemeralContainer Source = "ephemeral_container"
+	SourcePort               Source = "port"
+	SourceEnv                Source = "env"
+	SourceVolume             Source = "volume"
+)
+
+// subject describes the refused key in the messages.
+func subject(source Source, key string) string {
+	switch source {
+	case SourceLabel, SourceAnnotation:
+		return fmt.Sprintf("%s with key %s", source, key)
+	case SourceSelector:
+		return fmt.Sprintf("selector label key %s", key)
+	case SourcePathKey:
+		return fmt.Sprintf("key %s", key)
+	case SourcePathValue:
+		return fmt.Sprintf("value %s", key)
+	case SourceDataKey:
+		return fmt.Sprintf("data key %s", key)
+	case SourceName:
+		return fmt.Sprintf("name %s", key)
+	case SourceContainer:
+		return fmt.Sprintf("container name %s", key)
+	case SourceInitContainer:
+		return fmt.Sprintf("init container name %s", key)
+	case SourceEphemeralContainer:
+		return fmt.Sprintf("ephemeral container name %s", key)
+	case SourcePort:
+		return fmt.Sprintf("port name %s", key)
+	case SourceEnv:
+		return fmt.Sprintf("env var name %s", key)
+	case SourceVolume:
+		return fmt.Sprintf("volume name %s", key)
+	default:
+		return key
+	}
+}
+
+// Violation is a key refused by a rule, found at the JSON path. The other
+// fields are set only by the rules reporting them.
+type Violation struct {
+	Key         string
+	Path        string
+	Source      Source
+	Rule        Rule
+	Value       string
+	Offset      int
+	Distance    int
+	Detector    string
+	Explanation string
+}
+
+func (v Violation) String() string {
+	if v.Detector != "" {
+		reason := fmt.Sprintf("%s, found by the %s detector", v.Explanation, v.Detector)
+		return fmt.Sprintf("%s at %s not allowed, %s", subject(v.Source, v.Key), v.Path, reason)
+	}
+
+	var reason string
+	switch v.Rule {
+	case RulePalindrome:
+		reason = "the word is a palindrome"
+	case RuleDeniedLabelKey:
+		reason = "the key is denied by the denied_label_keys setting"
+	case RulePalindromeValue:
+		if v.Value == "" {
+			// values that could be secrets are not reported
+			reason = "the value is a palindrome"
+		} else {
+			reason = fmt.Sprintf("the value %s is a palindrome", v.Value)
+		}
+	case RulePalindromicSubstring:
+		reason = fmt.Sprintf("the substring %q at offset %d is a palindrome", v.Value, v.Offset)
+	case RuleBidiControl:
+		reason = "the key holds bidirectional control characters"
+	case RuleNearPalindrome:
+		reason = fmt.Sprintf("the word is %s away from the palindrome %q", edits(v.Distance), v.Value)
+	case RulePermutationPalindrome:
+		reason = fmt.Sprintf("the characters of the word can be rearranged in the palindrome %q", v.Value)
+	case RuleReversePair:
+		reason = fmt.Sprintf("the key is the reverse of the key %s", v.Value)
+	case RuleReverseValue:
+		reason = fmt.Sprintf("the key is the reverse of its value %s", v.Value)
+	}
+	return fmt.Sprintf("%s at %s not allowed, %s", subject(v.Source, v.Key), v.Path, reason)
+}
+
+// ViolationsError collects every violation found in an admitted object.
+type ViolationsError struct {
+	Violations []Violation
+}
+
+func (e ViolationsError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.String())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Keys returns the keys of the violations, in the same order.
+func (e ViolationsError) Keys() []string {
+	keys := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		keys = append(keys, v.Key)
+	}
+	return keys
+}
+
+func sortViolations(violations []Violation) {
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Key != violations[j].Key {
+			return violations[i].Key < violations[j].Key
+		}
+		if violations[i].Path != violations[j].Path {
+			return violations[i].Path < violations[j].Path
+		}
+		return violations[i].Rule < violations[j].Rule
+	})
+}
+
+// gjsonSpecialCharacters have a special meaning in a gjson path.
+const gjsonSpecialCharacters = `\.*?|#@`
+
+// jsonPath builds the reported path of a key inside the map found at
+// parentPath, quoting the keys that are not plain identifiers: label keys
+// often contain dots, like metadata.labels["app.kubernetes.io/name"].
+func jsonPath(parentPath, key string) string {
+	if isPlainPathKey(key) {
+		return parentPath + "." + key
+	}
+	return parentPath + "[" + strconv.Quote(key) + "]"
+}
+
+// isPlainPathKey reports whether the key can be appended to a path after a
+// dot: it is made of letters, digits, dashes and underscores and it does not
+// start with a digit, that would read as a list index.
+func isPlainPathKey(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// gjsonPath builds the gjson path of a key inside the map found at
+// parentPath, escaping the gjson special characters.
+func gjsonPath(parentPath, key string) string {
+	return parentPath + "." + escapeGJSONKey(key)
+}
+
+// escapeGJSONKey escapes the gjson special characters of the key, so that
+// it can be used as a path component.
+func escapeGJSONKey(key string) string {
+	var builder strings.Builder
+	builder.Grow(len(key))
+	for _, r := range key {
+		if strings.ContainsRune(gjsonSpecialCharacters, r) {
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,105 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/policy"
+	kubewarden_protocol "github.com/kubewarden/policy-sdk-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViolationsErrorMessage(t *testing.T) {
+	err := policy.ViolationsError{
+		Violations: []policy.Violation{
+			{Key: "aba", Path: "metadata.labels.aba", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+			{Key: "owner", Path: "metadata.labels.owner", Source: policy.SourceLabel, Rule: policy.RuleDeniedLabelKey},
+			{Key: "level", Path: "spec.template.metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+		},
+	}
+
+	assert.Equal(
+		t,
+		"label with key aba at metadata.labels.aba not allowed, the word is a palindrome; "+
+			"label with key owner at metadata.labels.owner not allowed, the key is denied by the denied_label_keys setting; "+
+			"label with key level at spec.template.metadata.labels.level not allowed, the word is a palindrome",
+		err.Error(),
+	)
+	assert.Equal(t, []string{"aba", "owner", "level"}, err.Keys())
+}
+
+func TestValidateLabelsReportsEveryViolation(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind: kubewarden_protocol.GroupVersionKind{Kind: "Deployment"},
+		Object: []byte(`{
+			"metadata": {
+				"labels": {"level": "1", "team": "a", "aba.aba": "2"}
+			},
+			"spec": {
+				"template": {
+					"metadata": {"labels": {"aba": "3", "level": "4", "env": "prod"}}
+				}
+			}
+		}`),
+	}
+
+	err := policy.ValidateLabels(&request, &policy.Settings{})
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{Key: "aba", Path: "spec.template.metadata.labels.aba", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+		{Key: "aba.aba", Path: `metadata.labels["aba.aba"]`, Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+		{Key: "level", Path: "metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+		{Key: "level", Path: "spec.template.metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+	}, violationsErr.Violations)
+}
+
+func TestValidateLabelsReportsDeniedKeys(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+		Object: []byte(`{"metadata": {"labels": {"level": "1", "owner": "a", "team": "b"}}}`),
+	}
+
+	err := policy.ValidateLabels(&request, &policy.Settings{DeniedLabelKeys: []string{"owner", "level"}})
+
+	var violationsErr policy.ViolationsError
+	require.ErrorAs(t, err, &violationsErr)
+	assert.Equal(t, []policy.Violation{
+		{Key: "level", Path: "metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RuleDeniedLabelKey},
+		{Key: "level", Path: "metadata.labels.level", Source: policy.SourceLabel, Rule: policy.RulePalindrome},
+		{Key: "owner", Path: "metadata.labels.owner", Source: policy.SourceLabel, Rule: policy.RuleDeniedLabelKey},
+	}, violationsErr.Violations)
+}
+
+func TestValidateLabelsWithoutViolations(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:   kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+		Object: []byte(`{"metadata": {"labels": {"level": "1", "team": "a"}}}`),
+	}
+
+	err := policy.ValidateLabels(&request, &policy.Settings{AllowedPalindromes: []string{"level"}})
+	assert.NoError(t, err)
+}
+
+func TestValidateLabelsReportsUnescapedPaths(t *testing.T) {
+	request := kubewarden_protocol.KubernetesAdmissionRequest{
+		Kind:      kubewarden_protocol.GroupVersionKind{Kind: "Pod"},
+		Operation: "UPDATE",
+		Object:    []byte(`{"metadata": {"labels": {"app.kubernetes.io/name": "x", "a.b.a": "x", "2002": "x"}}}`),
+		OldObject: []byte(`{"metadata": {"labels": {"a.b.a": "x"}}}`),
+	}
+
+	err := policy.ValidateLabels(&request, &policy.Settings{
+		NewViolationsOnly: true,
+		DeniedLabelKeys:   []string{"app.kubernetes.io/name"},
+	})
+
+	assert.EqualError(
+		t,
+		err,
+		`label with key 2002 at metadata.labels["2002"] not allowed, the word is a palindrome; `+
+			`label with key app.kubernetes.io/name at metadata.labels["app.kubernetes.io/name"] not allowed, `+
+			`the key is denied by the denied_label_keys setting`,
+	)
+}
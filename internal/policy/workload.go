@@ -0,0 +1,98 @@
+package policy
+
+import "github.com/tidwall/gjson"
+
+// podTemplatePath returns the gjson path of the pod template of the workload
+// kinds known by the SDK MutatePodSpecFromRequest helper.
+func podTemplatePath(kind string) (string, bool) {
+	switch kind {
+	case "Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "ReplicationController", "Job":
+		return "spec.template", true
+	case "CronJob":
+		return "spec.jobTemplate.spec.template", true
+	default:
+		return "", false
+	}
+}
+
+// workloadSelectorPath returns the gjson path of the selector of the
+// workload, with its form: the labels of the pod template must match it.
+func workloadSelectorPath(kind string) (string, selectorForm, bool) {
+	switch kind {
+	case "Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "Job":
+		return "spec.selector", selectorFormLabelSelector, true
+	case "ReplicationController":
+		return "spec.selector", selectorFormMap, true
+	case "CronJob":
+		return "spec.jobTemplate.spec.selector", selectorFormLabelSelector, true
+	default:
+		return "", selectorFormMap, false
+	}
+}
+
+// workloadSelectorKeys returns the label keys used by the selector of the
+// workload, found at the returned path.
+func workloadSelectorKeys(kind string, object gjson.Result) (string, map[string]bool) {
+	path, form, found := workloadSelectorPath(kind)
+	if !found {
+		return "", nil
+	}
+
+	keys := map[string]bool{}
+	addKeys := func(labels gjson.Result) {
+		labels.ForEach(func(key, _ gjson.Result) bool {
+			keys[key.String()] = true
+			return true
+		})
+	}
+	switch form {
+	case selectorFormMap:
+		addKeys(object.Get(path))
+	case selectorFormLabelSelector:
+		addKeys(object.Get(path + ".matchLabels"))
+		object.Get(path + ".matchExpressions").ForEach(func(_, expression gjson.Result) bool {
+			keys[expression.Get("key").String()] = true
+			return true
+		})
+	case selectorFormNodeSelectorTerm:
+		// not used by the workloads
+	}
+	return path, keys
+}
+
+// metadataPaths returns the gjson paths of the metadata to validate for the
+// given kind: the object metadata, for the CronJobs the metadata of the Jobs
+// they create and, for workloads, the pod template metadata.
+func metadataPaths(kind string) []string {
+	paths := []string{"metadata"}
+
+	if kind == "CronJob" {
+		paths = append(paths, "spec.jobTemplate.metadata")
+	}
+
+	if templatePath, found := podTemplatePath(kind); found {
+		paths = append(paths, templatePath+".metadata")
+	}
+
+	return paths
+}
+
+// labelsPaths returns the gjson paths of the labels maps to validate for
+// the given kind.
+func labelsPaths(kind string) []string {
+	return fieldPaths(metadataPaths(kind), "labels")
+}
+
+// annotationsPaths returns the gjson paths of the annotations maps to
+// validate for the given kind.
+func annotationsPaths(kind string) []string {
+	return fieldPaths(metadataPaths(kind), "annotations")
+}
+
+func fieldPaths(parentPaths []string, field string) []string {
+	paths := make([]string, 0, len(parentPaths))
+	for _, parentPath := range parentPaths {
+		paths = append(paths, parentPath+"."+field)
+	}
+	return paths
+}
@@ -0,0 +1,123 @@
+package word
+
+import (
+	"encoding/base64"
+	"unicode/utf8"
+)
+
+const (
+	// base64GroupSize is the length of an aligned group of base64 characters.
+	base64GroupSize = 4
+	// base64GroupBytes is the number of bytes decoded from a full group.
+	base64GroupBytes = 3
+)
+
+// base64Group is a decoded group of base64 characters.
+type base64Group struct {
+	index int
+	bytes [base64GroupBytes]byte
+	n     int
+}
+
+// base64Source reads the text decoding the groups of base64 characters on
+// demand. The last group read by each pointer is kept, so the scanner
+// decodes every group about once.
+type base64Source struct {
+	encoded       string
+	decodedLength int
+	front         base64Group
+	back          base64Group
+}
+
+// IsPalindromeBase64 is IsPalindromeString for the text encoded in standard
+// base64. The text is decoded group by group from both ends, never as a
+// whole, so the memory used does not grow with the text. Empty texts and
+// not valid base64 are not palindromes.
+func IsPalindromeBase64(encoded string) bool {
+	source, valid := newBase64Source(encoded)
+	if !valid || source.decodedLength == 0 {
+		return false
+	}
+	scanner := utf8Scanner[*base64Source]{text: &source}
+	return scanner.isPalindrome()
+}
+
+// newBase64Source decodes every group once to check the encoding and to
+// find the length of the decoded text, without keeping the decoded bytes.
+func newBase64Source(encoded string) (base64Source, bool) {
+	if len(encoded)%base64GroupSize != 0 {
+		return base64Source{}, false
+	}
+
+	groups := len(encoded) / base64GroupSize
+	// the pointers start out of the text, at its two ends
+	source := base64Source{encoded: encoded, front: base64Group{index: -1}, back: base64Group{index: groups}}
+	for index := range groups {
+		var group base64Group
+		if !source.decode(index, &group) {
+			return base64Source{}, false
+		}
+		// only the last group can be padded
+		if group.n < base64GroupBytes && index != groups-1 {
+			return base64Source{}, false
+		}
+		source.decodedLength += group.n
+	}
+	return source, true
+}
+
+func (s *base64Source) decode(index int, group *base64Group) bool {
+	var characters [base64GroupSize]byte
+	copy(characters[:], s.encoded[index*base64GroupSize:])
+	n, err := base64.StdEncoding.Decode(group.bytes[:], characters[:])
+	if err != nil {
+		return false
+	}
+	group.index = index
+	group.n = n
+	return true
+}
+
+// byteAt returns the decoded byte at the position, from the cached group of
+// the pointer closest to it.
+func (s *base64Source) byteAt(position int) byte {
+	index := position / base64GroupBytes
+	group := &s.back
+	if abs(index-s.front.index) <= abs(index-s.back.index) {
+		group = &s.front
+	}
+	if group.index != index {
+		// the groups have already been validated
+		s.decode(index, group)
+	}
+	return group.bytes[position%base64GroupBytes]
+}
+
+func (s *base64Source) length() int {
+	return s.decodedLength
+}
+
+func (s *base64Source) decodeRune(start, limit int) (rune, int) {
+	var buffer [utf8.UTFMax]byte
+	n := min(limit-start, utf8.UTFMax)
+	for i := range n {
+		buffer[i] = s.byteAt(start + i)
+	}
+	return utf8.DecodeRune(buffer[:n])
+}
+
+func (s *base64Source) decodeLastRune(limit, end int) (rune, int) {
+	var buffer [utf8.UTFMax]byte
+	n := min(end-limit, utf8.UTFMax)
+	for i := range n {
+		buffer[i] = s.byteAt(end - n + i)
+	}
+	return utf8.DecodeLastRune(buffer[:n])
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,69 @@
+package word_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPalindromeBase64(t *testing.T) {
+	type testCase struct {
+		name         string
+		encoded      string
+		isPalindrome bool
+	}
+	encode := func(text string) string {
+		return base64.StdEncoding.EncodeToString([]byte(text))
+	}
+
+	for _, tc := range []testCase{
+		{name: "no padding", encoded: encode("kayak?"), isPalindrome: false},
+		{name: "full groups palindrome", encoded: encode("abccba"), isPalindrome: true},
+		{name: "one padding character", encoded: encode("stats"), isPalindrome: true},
+		{name: "two padding characters", encoded: encode("kaya"), isPalindrome: false},
+		{name: "single byte", encoded: encode("a"), isPalindrome: true},
+		{name: "case insensitive", encoded: encode("RaceCar"), isPalindrome: true},
+		{name: "binary palindrome", encoded: encode("\x01\xff\x01"), isPalindrome: true},
+		{name: "multibyte across groups", encoded: encode("日本x本日"), isPalindrome: true},
+		{name: "empty", encoded: "", isPalindrome: false},
+		{name: "not aligned", encoded: "YWJh=", isPalindrome: false},
+		{name: "invalid characters", encoded: "YW*h", isPalindrome: false},
+		{name: "padding before the end", encoded: "YQ==YQ==", isPalindrome: false},
+		{name: "missing padding", encoded: "YWJhYg", isPalindrome: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.isPalindrome, word.IsPalindromeBase64(tc.encoded))
+		})
+	}
+}
+
+func TestIsPalindromeBase64AgreesWithIsPalindromeBytes(t *testing.T) {
+	for _, input := range []string{
+		"abcba", "abcab", "éaé", "👍🏽x👍🏽", "🇮🇹🇫🇷🇮🇹",
+		"🇮🇹🇹🇮", "a\r\na", "Σας", "a\xffa",
+	} {
+		encoded := base64.StdEncoding.EncodeToString([]byte(input))
+		assert.Equal(t, word.IsPalindromeBytes([]byte(input)), word.IsPalindromeBase64(encoded), input)
+	}
+}
+
+func TestIsPalindromeBase64MemoryDoesNotGrowWithTheText(t *testing.T) {
+	half := strings.Repeat("ab🇮🇹é👍🏽", 1<<14)
+	var reversed strings.Builder
+	clusters := word.Graphemes(half)
+	for i := len(clusters) - 1; i >= 0; i-- {
+		reversed.WriteString(clusters[i])
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(half + "x" + reversed.String()))
+	short := base64.StdEncoding.EncodeToString([]byte("aba"))
+
+	assert.True(t, word.IsPalindromeBase64(encoded))
+	assert.Equal(
+		t,
+		testing.AllocsPerRun(1, func() { word.IsPalindromeBase64(short) }),
+		testing.AllocsPerRun(1, func() { word.IsPalindromeBase64(encoded) }),
+	)
+}
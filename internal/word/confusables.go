@@ -0,0 +1,64 @@
+package word
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// The table of confusablePrototype is generated from the confusables.txt
+// mapping of Unicode Technical Standard #39. It holds the subset of the
+// mapping whose prototypes are a single Latin letter, digit or separator,
+// with the prototypes case folded: the characters are looked up after the
+// NFKC normalization, which already maps the compatibility forms, like the
+// fullwidth and the mathematical letters, and before the case folding,
+// which would turn the uppercase I, confusable with l, into i.
+//go:generate go run gen_confusables.go -output confusables_table.go
+
+// isInvisible reports whether the character is a default ignorable one,
+// like the format characters, the zero width joiners and spaces, the soft
+// hyphen and the variation selectors, rendered as nothing.
+func isInvisible(r rune) bool {
+	return unicode.In(r, unicode.Cf, unicode.Variation_Selector, unicode.Other_Default_Ignorable_Code_Point)
+}
+
+// Skeleton returns the skeleton of the word, in the sense of Unicode
+// Technical Standard #39: the invisible characters are removed, the word is
+// normalized, every confusable character is replaced by its prototype and
+// the case is folded, so that level, written with the Cyrillic letter
+// U+0435 in place of the first e, leveI, written with an uppercase I in
+// place of the last l, and level have the same skeleton.
+func Skeleton(word string) string {
+	visible := strings.Map(func(r rune) rune {
+		if isInvisible(r) {
+			return -1
+		}
+		return r
+	}, word)
+
+	// the prototypes could compose with the marks that follow them
+	skeleton := canonical(prototypes(norm.NFKC.String(visible)))
+	// the case folding could give other confusable characters
+	return canonical(prototypes(skeleton))
+}
+
+// prototypes replaces every confusable character of the word by its
+// prototype.
+func prototypes(word string) string {
+	return strings.Map(func(r rune) rune {
+		if prototype, found := confusablePrototype(r); found {
+			return prototype
+		}
+		return r
+	}, word)
+}
+
+// HasBidiControl reports whether the word holds a bidirectional control
+// character, like the overrides and the isolates, that can reorder how the
+// word is displayed.
+func HasBidiControl(word string) bool {
+	return strings.IndexFunc(word, func(r rune) bool {
+		return unicode.Is(unicode.Bidi_Control, r)
+	}) >= 0
+}
@@ -0,0 +1,101 @@
+package word_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkeleton(t *testing.T) {
+	type testCase struct {
+		name             string
+		inputString      string
+		expectedSkeleton string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:             "ascii word",
+			inputString:      "level",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "cyrillic e",
+			inputString:      "l\u0435vel",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "uppercase cyrillic letters",
+			inputString:      "\u0420\u0410\u0421",
+			expectedSkeleton: "pac",
+		},
+		{
+			name:             "greek omicron",
+			inputString:      "n\u03bfon",
+			expectedSkeleton: "noon",
+		},
+		{
+			name:             "uppercase i looking like l",
+			inputString:      "leveI",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "mixed case homoglyphs",
+			inputString:      "N\u039fOn",
+			expectedSkeleton: "noon",
+		},
+		{
+			name:             "digits looking like letters",
+			inputString:      "10l",
+			expectedSkeleton: "lol",
+		},
+		{
+			name:             "zero width space",
+			inputString:      "le\u200bvel",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "zero width joiner",
+			inputString:      "le\u200dvel",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "bidi override",
+			inputString:      "\u202elevel\u202c",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "soft hyphen and variation selector",
+			inputString:      "le\u00advel\ufe0f",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "hyphen looking like a dash",
+			inputString:      "aba\u2010aba",
+			expectedSkeleton: "aba-aba",
+		},
+		{
+			name:             "lisu letter and dental click looking like l",
+			inputString:      "\ua4f2eve\u01c0",
+			expectedSkeleton: "level",
+		},
+		{
+			name:             "accented cyrillic letter composed again",
+			inputString:      "\u0435\u0301",
+			expectedSkeleton: "\u00e9",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedSkeleton, word.Skeleton(tc.inputString))
+		})
+	}
+}
+
+func TestHasBidiControl(t *testing.T) {
+	assert.False(t, word.HasBidiControl("level"))
+	assert.False(t, word.HasBidiControl("le\u200bvel"))
+	assert.True(t, word.HasBidiControl("\u202elevel"))
+	assert.True(t, word.HasBidiControl("le\u2066vel\u2069"))
+	assert.True(t, word.HasBidiControl("level\u200f"))
+}
@@ -0,0 +1,230 @@
+package word
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Names of the detectors shipped with the package.
+const (
+	StrictDetectorName        = "strict"
+	CaseSensitiveDetectorName = "case_sensitive"
+	SegmentDetectorName       = "segment"
+	SubstringDetectorName     = "substring"
+	NearDetectorName          = "near_palindrome"
+	PermutationDetectorName   = "permutation"
+	BidiControlDetectorName   = "bidi_control"
+)
+
+// Match is a palindrome found by a detector in a word, or the part of the
+// word refused by the detectors of other rules.
+type Match struct {
+	// Text is the palindrome, the whole word or a part of it, normalized.
+	Text string
+	// Offset is where the palindrome starts, in normalized characters.
+	Offset int
+	// Distance is how many edits away from the palindrome the word is.
+	Distance int
+	// Explanation describes the palindrome in the messages.
+	Explanation string
+}
+
+// Detector finds a palindrome, or another reason to refuse it, in a word.
+type Detector interface {
+	// Name identifies the detector in the registry and in the messages.
+	Name() string
+	// Detect returns the palindrome found in the word, if any.
+	Detect(word string) (Match, bool)
+}
+
+// StrictDetector finds the words reading the same backward once normalized
+// and case folded, like IsPalindrome.
+type StrictDetector struct{}
+
+func (StrictDetector) Name() string {
+	return StrictDetectorName
+}
+
+func (StrictDetector) Detect(word string) (Match, bool) {
+	clusters := Normalize(word)
+	if !isMirrored(clusters) {
+		return Match{}, false
+	}
+	return Match{Text: strings.Join(clusters, ""), Explanation: "the word is a palindrome"}, true
+}
+
+// CaseSensitiveDetector finds the words reading the same backward once
+// normalized, without folding their case: Level is not a palindrome.
+type CaseSensitiveDetector struct{}
+
+func (CaseSensitiveDetector) Name() string {
+	return CaseSensitiveDetectorName
+}
+
+func (CaseSensitiveDetector) Detect(word string) (Match, bool) {
+	normalized := norm.NFKC.String(word)
+	if !isMirrored(Graphemes(normalized)) {
+		return Match{}, false
+	}
+	return Match{Text: normalized, Explanation: "the word is a palindrome, case included"}, true
+}
+
+// SegmentDetector finds the first separator delimited segment of the word
+// that is a palindrome.
+type SegmentDetector struct {
+	Separators string
+}
+
+func (SegmentDetector) Name() string {
+	return SegmentDetectorName
+}
+
+func (d SegmentDetector) Detect(word string) (Match, bool) {
+	start := 0
+	for start < len(word) {
+		end := len(word)
+		if i := strings.IndexFunc(word[start:], d.isSeparator); i >= 0 {
+			end = start + i
+		}
+		if segment := word[start:end]; segment != "" && IsPalindrome(segment) {
+			text := canonical(segment)
+			return Match{
+				Text:        text,
+				Offset:      Length(word[:start]),
+				Explanation: fmt.Sprintf("the segment %q is a palindrome", text),
+			}, true
+		}
+		if end == len(word) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(word[end:])
+		start = end + size
+	}
+	return Match{}, false
+}
+
+func (d SegmentDetector) isSeparator(r rune) bool {
+	return strings.ContainsRune(d.Separators, r)
+}
+
+// SubstringDetector finds the longest palindromic substring of the word,
+// when it is at least MinLength characters long.
+type SubstringDetector struct {
+	MinLength int
+}
+
+func (SubstringDetector) Name() string {
+	return SubstringDetectorName
+}
+
+func (d SubstringDetector) Detect(word string) (Match, bool) {
+	substring := LongestPalindrome(word)
+	if substring.Length == 0 || substring.Length < d.MinLength {
+		return Match{}, false
+	}
+	return Match{
+		Text:        substring.Text,
+		Offset:      substring.Offset,
+		Explanation: fmt.Sprintf("the substring %q at offset %d is a palindrome", substring.Text, substring.Offset),
+	}, true
+}
+
+// NearDetector finds the palindrome closest to the word, when the word is
+// not a palindrome but it is at most MaxDistance edits away from one.
+type NearDetector struct {
+	MaxDistance int
+}
+
+func (NearDetector) Name() string {
+	return NearDetectorName
+}
+
+func (d NearDetector) Detect(word string) (Match, bool) {
+	nearest, found := NearestPalindrome(word, d.MaxDistance)
+	if !found || nearest.Distance == 0 {
+		return Match{}, false
+	}
+	edits := "1 edit"
+	if nearest.Distance > 1 {
+		edits = fmt.Sprintf("%d edits", nearest.Distance)
+	}
+	return Match{
+		Text:        nearest.Closest,
+		Distance:    nearest.Distance,
+		Explanation: fmt.Sprintf("the word is %s away from the palindrome %q", edits, nearest.Closest),
+	}, true
+}
+
+// PermutationDetector finds the words whose characters can be rearranged
+// in a palindrome, like PermutationPalindrome.
+type PermutationDetector struct{}
+
+func (PermutationDetector) Name() string {
+	return PermutationDetectorName
+}
+
+func (PermutationDetector) Detect(word string) (Match, bool) {
+	palindrome, found := PermutationPalindrome(word)
+	if !found {
+		return Match{}, false
+	}
+	return Match{
+		Text:        palindrome,
+		Explanation: fmt.Sprintf("the characters can be rearranged in the palindrome %q", palindrome),
+	}, true
+}
+
+// BidiControlDetector finds the words holding bidirectional control
+// characters, like HasBidiControl. They are not palindromes, but they can
+// display one.
+type BidiControlDetector struct{}
+
+func (BidiControlDetector) Name() string {
+	return BidiControlDetectorName
+}
+
+func (BidiControlDetector) Detect(word string) (Match, bool) {
+	if !HasBidiControl(word) {
+		return Match{}, false
+	}
+	return Match{Text: word, Explanation: "the word holds bidirectional control characters"}, true
+}
+
+// Registry holds the detectors by name.
+type Registry struct {
+	detectors map[string]Detector
+}
+
+// NewRegistry returns a registry holding the given detectors.
+func NewRegistry(detectors ...Detector) *Registry {
+	r := &Registry{detectors: make(map[string]Detector, len(detectors))}
+	for _, detector := range detectors {
+		r.Register(detector)
+	}
+	return r
+}
+
+// Register adds the detector, replacing the one with the same name.
+func (r *Registry) Register(detector Detector) {
+	r.detectors[detector.Name()] = detector
+}
+
+// Lookup returns the detector with the given name.
+func (r *Registry) Lookup(name string) (Detector, bool) {
+	detector, found := r.detectors[name]
+	return detector, found
+}
+
+// Names returns the sorted names of the registered detectors.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.detectors))
+	for name := range r.detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,173 @@
+package word_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectors(t *testing.T) {
+	type testCase struct {
+		name          string
+		detector      word.Detector
+		inputString   string
+		expectedMatch word.Match
+		expectedFound bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:          "strict palindrome",
+			detector:      word.StrictDetector{},
+			inputString:   "Level",
+			expectedMatch: word.Match{Text: "level", Explanation: "the word is a palindrome"},
+			expectedFound: true,
+		},
+		{
+			name:          "strict detector on a word that is not a palindrome",
+			detector:      word.StrictDetector{},
+			inputString:   "levels",
+			expectedFound: false,
+		},
+		{
+			name:          "case sensitive palindrome",
+			detector:      word.CaseSensitiveDetector{},
+			inputString:   "LeveL",
+			expectedMatch: word.Match{Text: "LeveL", Explanation: "the word is a palindrome, case included"},
+			expectedFound: true,
+		},
+		{
+			name:          "case sensitive detector on a palindrome with different cases",
+			detector:      word.CaseSensitiveDetector{},
+			inputString:   "Level",
+			expectedFound: false,
+		},
+		{
+			name:        "palindrome segment",
+			detector:    word.SegmentDetector{Separators: "-."},
+			inputString: "team.Level-x",
+			expectedMatch: word.Match{
+				Text:        "level",
+				Offset:      5,
+				Explanation: `the segment "level" is a palindrome`,
+			},
+			expectedFound: true,
+		},
+		{
+			name:          "segment detector without separators",
+			detector:      word.SegmentDetector{},
+			inputString:   "aba",
+			expectedMatch: word.Match{Text: "aba", Explanation: `the segment "aba" is a palindrome`},
+			expectedFound: true,
+		},
+		{
+			name:          "segment detector on a word without palindrome segments",
+			detector:      word.SegmentDetector{Separators: "-"},
+			inputString:   "team--web-",
+			expectedFound: false,
+		},
+		{
+			name:        "palindromic substring",
+			detector:    word.SubstringDetector{MinLength: 5},
+			inputString: "teamracecarprod",
+			expectedMatch: word.Match{
+				Text:        "racecar",
+				Offset:      4,
+				Explanation: `the substring "racecar" at offset 4 is a palindrome`,
+			},
+			expectedFound: true,
+		},
+		{
+			name:          "palindromic substring shorter than the minimum length",
+			detector:      word.SubstringDetector{MinLength: 8},
+			inputString:   "teamracecarprod",
+			expectedFound: false,
+		},
+		{
+			name:        "near palindrome",
+			detector:    word.NearDetector{MaxDistance: 2},
+			inputString: "levels",
+			expectedMatch: word.Match{
+				Text:        "level",
+				Distance:    1,
+				Explanation: `the word is 1 edit away from the palindrome "level"`,
+			},
+			expectedFound: true,
+		},
+		{
+			name:          "near palindrome detector on a palindrome",
+			detector:      word.NearDetector{MaxDistance: 2},
+			inputString:   "level",
+			expectedFound: false,
+		},
+		{
+			name:        "permutation palindrome",
+			detector:    word.PermutationDetector{},
+			inputString: "ivicc",
+			expectedMatch: word.Match{
+				Text:        "icvci",
+				Explanation: `the characters can be rearranged in the palindrome "icvci"`,
+			},
+			expectedFound: true,
+		},
+		{
+			name:          "permutation detector on a word without permutation palindromes",
+			detector:      word.PermutationDetector{},
+			inputString:   "abc",
+			expectedFound: false,
+		},
+		{
+			name:        "bidirectional control characters",
+			detector:    word.BidiControlDetector{},
+			inputString: "ab\u202ec",
+			expectedMatch: word.Match{
+				Text:        "ab\u202ec",
+				Explanation: "the word holds bidirectional control characters",
+			},
+			expectedFound: true,
+		},
+		{
+			name:          "bidirectional control detector on a plain word",
+			detector:      word.BidiControlDetector{},
+			inputString:   "abc",
+			expectedFound: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			match, found := tc.detector.Detect(tc.inputString)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedMatch, match)
+		})
+	}
+}
+
+// reversedDetector finds the words ending with their first character.
+type reversedDetector struct{}
+
+func (reversedDetector) Name() string {
+	return "strict"
+}
+
+func (reversedDetector) Detect(w string) (word.Match, bool) {
+	if w == "" || w[0] != w[len(w)-1] {
+		return word.Match{}, false
+	}
+	return word.Match{Text: w, Explanation: "the word ends as it starts"}, true
+}
+
+func TestRegistry(t *testing.T) {
+	registry := word.NewRegistry(word.StrictDetector{}, word.SubstringDetector{MinLength: 3})
+
+	assert.Equal(t, []string{"strict", "substring"}, registry.Names())
+	detector, found := registry.Lookup("substring")
+	assert.True(t, found)
+	assert.Equal(t, word.SubstringDetector{MinLength: 3}, detector)
+	_, found = registry.Lookup("segment")
+	assert.False(t, found)
+
+	registry.Register(reversedDetector{})
+	detector, found = registry.Lookup("strict")
+	assert.True(t, found)
+	assert.Equal(t, reversedDetector{}, detector)
+}
@@ -0,0 +1,140 @@
+package word
+
+import "strings"
+
+// NearPalindrome is the palindrome closest to a word and how many edits,
+// insertions, deletions or substitutions of a character, it is away.
+type NearPalindrome struct {
+	Closest  string
+	Distance int
+}
+
+// NearestPalindrome returns the palindrome closest to the normalized word,
+// when it is at most maxDistance edits away.
+//
+// Making a word a palindrome pairs its characters from both ends: a
+// deletion, or the equivalent insertion, skips a character of one end and
+// a substitution pairs two different characters. After a edits of the left
+// end and b of the right end the two ends are shifted by |a-b|, so only the
+// states within maxDistance of the diagonal are computed, in O(n*k) time
+// instead of the O(n^2) of the full dynamic programming. Substituting one
+// character of every pair makes any word a palindrome, so the band never
+// grows wider than the word.
+func NearestPalindrome(word string, maxDistance int) (NearPalindrome, bool) {
+	if maxDistance < 0 {
+		return NearPalindrome{}, false
+	}
+	clusters := Normalize(word)
+	table := newDistanceTable(clusters, min(maxDistance, len(clusters)/2))
+	distance := table.distance(0, 0)
+	if distance > maxDistance {
+		return NearPalindrome{}, false
+	}
+	return NearPalindrome{Closest: table.closest(), Distance: distance}, true
+}
+
+// distanceTable holds, for every state within the band, the edits needed to
+// make a palindrome of the clusters left once left clusters are consumed
+// from the start and right clusters from the end. The distances are capped
+// at maxDistance+1.
+type distanceTable struct {
+	clusters    []string
+	maxDistance int
+	width       int
+	distances   []int
+}
+
+func newDistanceTable(clusters []string, maxDistance int) *distanceTable {
+	width := 2*maxDistance + 1
+	t := &distanceTable{
+		clusters:    clusters,
+		maxDistance: maxDistance,
+		width:       width,
+		distances:   make([]int, (len(clusters)+1)*width),
+	}
+	// every state depends on the states consuming one more cluster
+	for consumed := len(clusters); consumed >= 0; consumed-- {
+		for left := range consumed + 1 {
+			if t.inBand(left, consumed-left) {
+				t.distances[t.index(left, consumed-left)] = t.compute(left, consumed-left)
+			}
+		}
+	}
+	return t
+}
+
+func (t *distanceTable) inBand(left, right int) bool {
+	return left-right <= t.maxDistance && right-left <= t.maxDistance
+}
+
+func (t *distanceTable) index(left, right int) int {
+	return left*t.width + right - left + t.maxDistance
+}
+
+// distance returns the edits of the state, the states out of the band are
+// too far.
+func (t *distanceTable) distance(left, right int) int {
+	if !t.inBand(left, right) {
+		return t.maxDistance + 1
+	}
+	return t.distances[t.index(left, right)]
+}
+
+// remaining returns how many clusters the state has still to pair.
+func (t *distanceTable) remaining(left, right int) int {
+	return len(t.clusters) - left - right
+}
+
+func (t *distanceTable) compute(left, right int) int {
+	if t.remaining(left, right) <= 1 {
+		return 0
+	}
+	if t.clusters[left] == t.clusters[len(t.clusters)-1-right] {
+		return t.distance(left+1, right+1)
+	}
+	best := t.distance(left+1, right+1)
+	if d := t.distance(left, right+1); d < best {
+		best = d
+	}
+	if d := t.distance(left+1, right); d < best {
+		best = d
+	}
+	if best > t.maxDistance {
+		return t.maxDistance + 1
+	}
+	return best + 1
+}
+
+// closest rebuilds the palindrome following the cheapest edits from the
+// initial state, preferring the substitutions, that keep the length of the
+// word, to the deletions.
+func (t *distanceTable) closest() string {
+	var half []string
+	left, right := 0, 0
+	for t.remaining(left, right) > 1 {
+		current := t.distance(left, right)
+		start, end := t.clusters[left], t.clusters[len(t.clusters)-1-right]
+		switch {
+		case start == end || t.distance(left+1, right+1) == current-1:
+			// a pair, or a substitution writing the start cluster at the end
+			half = append(half, start)
+			left, right = left+1, right+1
+		case t.distance(left, right+1) == current-1:
+			right++
+		default:
+			left++
+		}
+	}
+
+	var builder strings.Builder
+	for _, cluster := range half {
+		builder.WriteString(cluster)
+	}
+	if t.remaining(left, right) == 1 {
+		builder.WriteString(t.clusters[left])
+	}
+	for i := len(half) - 1; i >= 0; i-- {
+		builder.WriteString(half[i])
+	}
+	return builder.String()
+}
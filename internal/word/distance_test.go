@@ -0,0 +1,126 @@
+package word_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestPalindrome(t *testing.T) {
+	type testCase struct {
+		inputString     string
+		maxDistance     int
+		expectedFound   bool
+		expectedNearest word.NearPalindrome
+	}
+
+	for _, tc := range []testCase{
+		{
+			inputString:     "level",
+			maxDistance:     0,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "level", Distance: 0},
+		},
+		{
+			inputString:   "levels",
+			maxDistance:   0,
+			expectedFound: false,
+		},
+		{
+			inputString:     "levels",
+			maxDistance:     1,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "level", Distance: 1},
+		},
+		{
+			inputString:     "slevel",
+			maxDistance:     1,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "level", Distance: 1},
+		},
+		{
+			inputString:   "levle",
+			maxDistance:   1,
+			expectedFound: false,
+		},
+		{
+			inputString:     "levle",
+			maxDistance:     2,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "level", Distance: 2},
+		},
+		{
+			inputString:     "radcr",
+			maxDistance:     1,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "radar", Distance: 1},
+		},
+		{
+			inputString:     "ab",
+			maxDistance:     1,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "aa", Distance: 1},
+		},
+		{
+			inputString:     "LEVELS",
+			maxDistance:     3,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "level", Distance: 1},
+		},
+		{
+			inputString:   "nginx",
+			maxDistance:   1,
+			expectedFound: false,
+		},
+		{
+			inputString:     "",
+			maxDistance:     0,
+			expectedFound:   true,
+			expectedNearest: word.NearPalindrome{Closest: "", Distance: 0},
+		},
+		{
+			inputString:   "level",
+			maxDistance:   -1,
+			expectedFound: false,
+		},
+	} {
+		t.Run(tc.inputString, func(t *testing.T) {
+			nearest, found := word.NearestPalindrome(tc.inputString, tc.maxDistance)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedNearest, nearest)
+		})
+	}
+}
+
+func TestNearestPalindromeThreshold(t *testing.T) {
+	// every edit of the key moves it one more edit away from the palindrome
+	key := "abcdefgh"
+	for maxDistance := range 5 {
+		nearest, found := word.NearestPalindrome(key, maxDistance)
+		assert.Equal(t, maxDistance >= 4, found)
+		if found {
+			assert.Equal(t, 4, nearest.Distance)
+			assert.Equal(t, "abcddcba", nearest.Closest)
+		}
+	}
+}
+
+func TestNearestPalindromeOfLongWords(t *testing.T) {
+	palindrome := strings.Repeat("ab", 500) + strings.Repeat("ba", 500)
+
+	nearest, found := word.NearestPalindrome("x"+palindrome+"y", 2)
+
+	assert.True(t, found)
+	assert.Equal(t, 1, nearest.Distance)
+	assert.Equal(t, "x"+palindrome+"x", nearest.Closest)
+}
+
+func TestNearestPalindromeWithHugeDistance(t *testing.T) {
+	nearest, found := word.NearestPalindrome("abc", 1<<40)
+
+	assert.True(t, found)
+	assert.Equal(t, 1, nearest.Distance)
+	assert.Equal(t, "aba", nearest.Closest)
+}
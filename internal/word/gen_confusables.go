@@ -0,0 +1,183 @@
+//go:build ignore
+
+// gen_confusables generates confusables_table.go from the confusables.txt
+// mapping of Unicode Technical Standard #39. Run it with go generate, the
+// mapping is downloaded unless a local copy is given with -input.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	unicodeVersion = "17.0.0"
+	confusablesURL = "https://www.unicode.org/Public/" + unicodeVersion + "/security/confusables.txt"
+	// prototypes are the characters kept as prototypes: the Latin letters,
+	// the digits and the separators of the label keys, case folded.
+	prototypes = "abcdefghijklmnopqrstuvwxyz0123456789-_./"
+	// casesPerLine wraps the long case lists of the generated switch.
+	casesPerLine = 8
+)
+
+func main() {
+	input := flag.String("input", "", "local copy of confusables.txt, downloaded when empty")
+	output := flag.String("output", "confusables_table.go", "generated file")
+	flag.Parse()
+
+	data, err := readConfusables(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sources, err := parseConfusables(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	source, err := format.Source(generate(sources))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, source, 0o600); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readConfusables(path string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	response, err := http.Get(confusablesURL) //nolint:noctx // run by hand with go generate
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download %s: %s", confusablesURL, response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
+// parseConfusables returns the characters of every prototype. Only the
+// characters surviving the NFKC normalization are kept, the policy looks
+// them up after it and before the case folding, like the skeleton of
+// Unicode Technical Standard #39 does, and only the prototypes that are a
+// single character of prototypes once folded.
+func parseConfusables(data []byte) (map[rune][]rune, error) {
+	sources := make(map[rune][]rune)
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		source, err := parseCodePoints(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		target, err := parseCodePoints(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if utf8.RuneCountInString(source) != 1 || norm.NFKC.String(source) != source {
+			continue
+		}
+		prototype := folded(target)
+		if utf8.RuneCountInString(prototype) != 1 || !strings.Contains(prototypes, prototype) || prototype == folded(source) {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(prototype)
+		s, _ := utf8.DecodeRuneInString(source)
+		sources[r] = append(sources[r], s)
+	}
+	return sources, scanner.Err()
+}
+
+func parseCodePoints(field string) (string, error) {
+	var builder strings.Builder
+	for _, hex := range strings.Fields(field) {
+		r, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("%s is not a code point: %w", hex, err)
+		}
+		builder.WriteRune(rune(r))
+	}
+	return builder.String(), nil
+}
+
+// folded returns the text NFKC normalized and case folded, like the policy
+// compares it. The generator does not import the package it generates, the
+// full case foldings are not needed: none of them is a single prototype.
+func folded(text string) string {
+	return norm.NFKC.String(strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, norm.NFKC.String(text)))
+}
+
+func generate(sources map[rune][]rune) []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, `// Code generated by gen_confusables.go from confusables.txt %s; DO NOT EDIT.
+
+package word
+
+// confusablePrototype returns the prototype of the character in the
+// confusables.txt mapping of Unicode Technical Standard #39.
+func confusablePrototype(r rune) (rune, bool) {
+	switch r {
+`, unicodeVersion)
+
+	keys := make([]rune, 0, len(sources))
+	for prototype := range sources {
+		keys = append(keys, prototype)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, prototype := range keys {
+		characters := sources[prototype]
+		sort.Slice(characters, func(i, j int) bool { return characters[i] < characters[j] })
+		buffer.WriteString("\tcase ")
+		for i, character := range characters {
+			switch {
+			case i == 0:
+			case i%casesPerLine == 0:
+				buffer.WriteString(",\n\t\t")
+			default:
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(runeLiteral(character))
+		}
+		fmt.Fprintf(&buffer, ":\n\t\treturn %s, true\n", runeLiteral(prototype))
+	}
+
+	buffer.WriteString("\tdefault:\n\t\treturn 0, false\n\t}\n}\n")
+	return buffer.Bytes()
+}
+
+// runeLiteral escapes every character outside of the printable ASCII, so
+// that no confusable or invisible character is written in the source.
+func runeLiteral(r rune) string {
+	switch {
+	case r == '\'' || r == '\\':
+		return `'\` + string(r) + `'`
+	case r >= ' ' && r <= '~':
+		return "'" + string(r) + "'"
+	case r <= 0xffff:
+		return fmt.Sprintf(`'\u%04x'`, r)
+	default:
+		return fmt.Sprintf(`'\U%08x'`, r)
+	}
+}
@@ -0,0 +1,80 @@
+package word
+
+import "unicode"
+
+const (
+	zeroWidthJoiner = '\u200d'
+	carriageReturn  = '\r'
+	lineFeed        = '\n'
+
+	// Emoji skin tone modifiers, they are symbols but extend the previous emoji.
+	firstEmojiModifier = '\U0001F3FB'
+	lastEmojiModifier  = '\U0001F3FF'
+	// Tag characters used by emoji flag sequences, like the subdivision flags.
+	firstEmojiTag = '\U000E0020'
+	lastEmojiTag  = '\U000E007F'
+
+	firstRegionalIndicator = '\U0001F1E6'
+	lastRegionalIndicator  = '\U0001F1FF'
+	// A flag is made of a pair of regional indicators.
+	regionalIndicatorsInFlag = 2
+)
+
+// isExtend reports whether the rune attaches to the previous grapheme cluster.
+func isExtend(r rune) bool {
+	return r == zeroWidthJoiner ||
+		(r >= firstEmojiModifier && r <= lastEmojiModifier) ||
+		(r >= firstEmojiTag && r <= lastEmojiTag) ||
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= firstRegionalIndicator && r <= lastRegionalIndicator
+}
+
+// Graphemes splits the string in user perceived characters, following a
+// simplified version of the extended grapheme cluster boundaries of UAX #29:
+// combining marks, emoji modifiers and ZWJ sequences stay attached to their
+// base character and regional indicators are paired in flags.
+func Graphemes(s string) []string {
+	clusters := make([]string, 0, len(s))
+
+	start := 0
+	var previous rune
+	regionalIndicatorsInCluster := 0
+	for i, r := range s {
+		if i > 0 && !continuesCluster(previous, r, regionalIndicatorsInCluster) {
+			clusters = append(clusters, s[start:i])
+			start = i
+			regionalIndicatorsInCluster = 0
+		}
+		if isRegionalIndicator(r) {
+			regionalIndicatorsInCluster++
+		}
+		previous = r
+	}
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+
+	return clusters
+}
+
+func continuesCluster(previous, current rune, regionalIndicatorsInCluster int) bool {
+	switch {
+	case previous == carriageReturn && current == lineFeed:
+		return true
+	case previous == carriageReturn || previous == lineFeed:
+		return false
+	case isExtend(current):
+		return true
+	case previous == zeroWidthJoiner:
+		// ZWJ sequences, like the family emoji, join symbols together
+		return unicode.Is(unicode.So, current)
+	case isRegionalIndicator(previous) && isRegionalIndicator(current):
+		// flags are made of pairs of regional indicators
+		return regionalIndicatorsInCluster%regionalIndicatorsInFlag == 1
+	default:
+		return false
+	}
+}
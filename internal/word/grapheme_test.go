@@ -0,0 +1,58 @@
+package word_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphemes(t *testing.T) {
+	type testCase struct {
+		name             string
+		inputString      string
+		expectedClusters []string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:             "ascii characters are clusters on their own",
+			inputString:      "aba",
+			expectedClusters: []string{"a", "b", "a"},
+		},
+		{
+			name:             "combining marks stay attached to the base character",
+			inputString:      "éṭ",
+			expectedClusters: []string{"é", "ṭ"},
+		},
+		{
+			name:             "emoji zwj sequences are a single cluster",
+			inputString:      "x👨‍👩‍👧y",
+			expectedClusters: []string{"x", "👨‍👩‍👧", "y"},
+		},
+		{
+			name:             "emoji modifiers stay attached to the emoji",
+			inputString:      "👍🏽👍",
+			expectedClusters: []string{"👍🏽", "👍"},
+		},
+		{
+			name:             "regional indicators are paired in flags",
+			inputString:      "🇮🇹🇫🇷🇩",
+			expectedClusters: []string{"🇮🇹", "🇫🇷", "🇩"},
+		},
+		{
+			name:             "crlf is a single cluster",
+			inputString:      "a\r\nb",
+			expectedClusters: []string{"a", "\r\n", "b"},
+		},
+		{
+			name:             "empty string has no clusters",
+			inputString:      "",
+			expectedClusters: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedClusters, word.Graphemes(tc.inputString))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package word
+
+import "strings"
+
+// Substring is a part of a normalized word, its offset and its length are
+// counted in normalized characters.
+type Substring struct {
+	Text   string
+	Offset int
+	Length int
+}
+
+// LongestPalindrome returns the longest substring of the normalized word
+// reading the same backward, the leftmost one when several are as long.
+// It runs in linear time using the Manacher's algorithm.
+func LongestPalindrome(word string) Substring {
+	clusters := Normalize(word)
+	if len(clusters) == 0 {
+		return Substring{}
+	}
+
+	// The clusters are interleaved with virtual separators, at the even
+	// positions, so that the odd and the even palindromes both have a center.
+	// radius[i] is the length, in clusters, of the palindrome centered at i.
+	size := 2*len(clusters) + 1
+	radius := make([]int, size)
+	center, right := 0, 0
+	best := 0
+	for i := range size {
+		if i < right {
+			radius[i] = right - i
+			if mirrored := radius[2*center-i]; mirrored < radius[i] {
+				radius[i] = mirrored
+			}
+		}
+		for i-radius[i]-1 >= 0 && i+radius[i]+1 < size &&
+			equalAt(clusters, i-radius[i]-1, i+radius[i]+1) {
+			radius[i]++
+		}
+		if i+radius[i] > right {
+			center, right = i, i+radius[i]
+		}
+		if radius[i] > radius[best] {
+			best = i
+		}
+	}
+
+	offset := (best - radius[best]) / 2
+	return Substring{
+		Text:   strings.Join(clusters[offset:offset+radius[best]], ""),
+		Offset: offset,
+		Length: radius[best],
+	}
+}
+
+// equalAt compares two positions of the interleaved clusters, the virtual
+// separators are all equal.
+func equalAt(clusters []string, a, b int) bool {
+	if a%2 == 0 {
+		return true
+	}
+	return clusters[a/2] == clusters[b/2]
+}
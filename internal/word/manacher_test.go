@@ -0,0 +1,73 @@
+package word_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongestPalindrome(t *testing.T) {
+	type testCase struct {
+		inputString       string
+		expectedSubstring word.Substring
+	}
+
+	for _, tc := range []testCase{
+		{
+			inputString:       "",
+			expectedSubstring: word.Substring{},
+		},
+		{
+			inputString:       "x",
+			expectedSubstring: word.Substring{Text: "x", Offset: 0, Length: 1},
+		},
+		{
+			inputString:       "team-racecar-prod",
+			expectedSubstring: word.Substring{Text: "-racecar-", Offset: 4, Length: 9},
+		},
+		{
+			inputString:       "teamracecarprod",
+			expectedSubstring: word.Substring{Text: "racecar", Offset: 4, Length: 7},
+		},
+		{
+			inputString:       "xabba",
+			expectedSubstring: word.Substring{Text: "abba", Offset: 1, Length: 4},
+		},
+		{
+			inputString:       "level",
+			expectedSubstring: word.Substring{Text: "level", Offset: 0, Length: 5},
+		},
+		{
+			inputString:       "abcd",
+			expectedSubstring: word.Substring{Text: "a", Offset: 0, Length: 1},
+		},
+		{
+			inputString:       "abaxcdc",
+			expectedSubstring: word.Substring{Text: "aba", Offset: 0, Length: 3},
+		},
+		{
+			inputString:       "app-NOON",
+			expectedSubstring: word.Substring{Text: "noon", Offset: 4, Length: 4},
+		},
+		{
+			inputString:       "x-été",
+			expectedSubstring: word.Substring{Text: "été", Offset: 2, Length: 3},
+		},
+	} {
+		t.Run(tc.inputString, func(t *testing.T) {
+			assert.Equal(t, tc.expectedSubstring, word.LongestPalindrome(tc.inputString))
+		})
+	}
+}
+
+func TestLongestPalindromeOfLongWords(t *testing.T) {
+	palindrome := strings.Repeat("ab", 500) + "c" + strings.Repeat("ba", 500)
+
+	substring := word.LongestPalindrome("x" + palindrome + "y")
+
+	assert.Equal(t, palindrome, substring.Text)
+	assert.Equal(t, 1, substring.Offset)
+	assert.Equal(t, len(palindrome), substring.Length)
+}
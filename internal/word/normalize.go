@@ -0,0 +1,45 @@
+package word
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// caseFold applies the full case folding of the Unicode CaseFolding.txt,
+// statuses C and F, to the string.
+func caseFold(s string) string {
+	return cases.Fold().String(s)
+}
+
+// Normalize returns the grapheme clusters of the word after the NFKC
+// normalization and the full case folding, so that visually identical
+// words have the same clusters.
+func Normalize(word string) []string {
+	return Graphemes(canonical(word))
+}
+
+// Length returns how many characters the word has once normalized.
+func Length(word string) int {
+	return len(Normalize(word))
+}
+
+// Equal reports whether the words are the same once normalized.
+func Equal(a, b string) bool {
+	if a == b {
+		return true
+	}
+	return canonical(a) == canonical(b)
+}
+
+// Fold returns the word NFKC normalized and case folded, the form compared
+// by Equal.
+func Fold(word string) string {
+	return canonical(word)
+}
+
+// canonical folds the NFKC normalized word, normalizing it again because
+// folding could break the normalization, for example İ is folded into a
+// decomposed sequence.
+func canonical(word string) string {
+	return norm.NFKC.String(caseFold(norm.NFKC.String(word)))
+}
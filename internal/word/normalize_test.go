@@ -0,0 +1,66 @@
+package word_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	type testCase struct {
+		name             string
+		inputString      string
+		expectedClusters []string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:             "decomposed characters are composed",
+			inputString:      "Café",
+			expectedClusters: []string{"c", "a", "f", "\u00e9"},
+		},
+		{
+			name:             "compatibility characters are replaced",
+			inputString:      "ﬁ①",
+			expectedClusters: []string{"f", "i", "1"},
+		},
+		{
+			name:             "full case folding expands characters",
+			inputString:      "Straße",
+			expectedClusters: []string{"s", "t", "r", "a", "s", "s", "e"},
+		},
+		{
+			name:             "case folding orbits are folded to the same character",
+			inputString:      "Σσς",
+			expectedClusters: []string{"σ", "σ", "σ"},
+		},
+		{
+			name:             "folded characters are normalized again",
+			inputString:      "İ",
+			expectedClusters: []string{"i\u0307"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedClusters, word.Normalize(tc.inputString))
+		})
+	}
+}
+
+func TestLength(t *testing.T) {
+	assert.Equal(t, 0, word.Length(""))
+	assert.Equal(t, 5, word.Length("level"))
+	assert.Equal(t, 3, word.Length("e\u0301te\u0301"))
+	assert.Equal(t, 2, word.Length("ß"))
+	assert.Equal(t, 1, word.Length("👍🏽"))
+}
+
+func TestEqual(t *testing.T) {
+	assert.True(t, word.Equal("level", "level"))
+	assert.True(t, word.Equal("été", "été"))
+	assert.True(t, word.Equal("STRASSE", "straße"))
+	assert.True(t, word.Equal("\u1f80", "\u1f00\u03b9"))
+	assert.True(t, word.Equal("\u1ff7", "\u03c9\u0342\u03b9"))
+	assert.True(t, word.Equal("\u1fb2", "\u1f70\u03b9"))
+	assert.False(t, word.Equal("level", "levels"))
+}
@@ -1,14 +1,18 @@
 package word
 
-import "strings"
-
+// IsPalindrome reports whether the word reads the same backward, comparing
+// the normalized grapheme clusters of the word.
 func IsPalindrome(word string) bool {
-	normalizedRunes := []rune(strings.ToLower(word))
+	return isMirrored(Normalize(word))
+}
+
+// isMirrored reports whether the clusters are the same in reverse order.
+func isMirrored(clusters []string) bool {
 	var i, j int
-	// double pointers, one at start one at the end of string
-	for i = range len(normalizedRunes) / 2 {
-		j = len(normalizedRunes) - 1 - i
-		if normalizedRunes[i] != normalizedRunes[j] {
+	// double pointers, one at start one at the end of the clusters
+	for i = range len(clusters) / 2 {
+		j = len(clusters) - 1 - i
+		if clusters[i] != clusters[j] {
 			return false
 		}
 	}
@@ -35,6 +35,50 @@ func TestIsPalindrome(t *testing.T) {
 			inputString:    "level",
 			expectedResult: true,
 		},
+		{
+			inputString:    "été",
+			expectedResult: true,
+		},
+		{
+			inputString:    "e\u0301te\u0301",
+			expectedResult: true,
+		},
+		{
+			inputString:    "\u00e9te\u0301",
+			expectedResult: true,
+		},
+		{
+			inputString:    "\u00e9te",
+			expectedResult: false,
+		},
+		{
+			inputString:    "a👨‍👩‍👧b👨‍👩‍👧a",
+			expectedResult: true,
+		},
+		{
+			inputString:    "👨‍👩‍👧a👩‍👨‍👧",
+			expectedResult: false,
+		},
+		{
+			inputString:    "🇮🇹x🇮🇹",
+			expectedResult: true,
+		},
+		{
+			inputString:    "sSß",
+			expectedResult: true,
+		},
+		{
+			inputString:    "Σας",
+			expectedResult: true,
+		},
+		{
+			inputString:    "ﬁxif",
+			expectedResult: true,
+		},
+		{
+			inputString:    "",
+			expectedResult: true,
+		},
 	} {
 		expectationText := "be a palindrome"
 		if !tc.expectedResult {
@@ -0,0 +1,44 @@
+package word
+
+import "strings"
+
+// PermutationPalindrome returns a palindrome made of the normalized
+// characters of the word, when they can be rearranged in one: at most one
+// character can appear an odd number of times. The characters of the
+// palindrome are in the order of their first appearance in the word, so
+// that ivicc gives icvci.
+func PermutationPalindrome(word string) (string, bool) {
+	clusters := Normalize(word)
+	counts := make(map[string]int, len(clusters))
+	var order []string
+	for _, cluster := range clusters {
+		if counts[cluster] == 0 {
+			order = append(order, cluster)
+		}
+		counts[cluster]++
+	}
+
+	half := make([]string, 0, len(clusters)/2)
+	var middle string
+	for _, cluster := range order {
+		if counts[cluster]%2 == 1 {
+			if middle != "" {
+				return "", false
+			}
+			middle = cluster
+		}
+		for range counts[cluster] / 2 {
+			half = append(half, cluster)
+		}
+	}
+
+	var builder strings.Builder
+	for _, cluster := range half {
+		builder.WriteString(cluster)
+	}
+	builder.WriteString(middle)
+	for i := len(half) - 1; i >= 0; i-- {
+		builder.WriteString(half[i])
+	}
+	return builder.String(), true
+}
@@ -0,0 +1,65 @@
+package word_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermutationPalindrome(t *testing.T) {
+	type testCase struct {
+		inputString        string
+		expectedPalindrome string
+		expectedFound      bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			inputString:        "civic",
+			expectedPalindrome: "civic",
+			expectedFound:      true,
+		},
+		{
+			inputString:        "ivicc",
+			expectedPalindrome: "icvci",
+			expectedFound:      true,
+		},
+		{
+			inputString:        "aabb",
+			expectedPalindrome: "abba",
+			expectedFound:      true,
+		},
+		{
+			inputString:        "CivIC",
+			expectedPalindrome: "civic",
+			expectedFound:      true,
+		},
+		{
+			inputString:        "ééta",
+			expectedPalindrome: "",
+			expectedFound:      false,
+		},
+		{
+			inputString:        "étté",
+			expectedPalindrome: "étté",
+			expectedFound:      true,
+		},
+		{
+			inputString:        "nginx",
+			expectedPalindrome: "",
+			expectedFound:      false,
+		},
+		{
+			inputString:        "",
+			expectedPalindrome: "",
+			expectedFound:      true,
+		},
+	} {
+		t.Run(tc.inputString, func(t *testing.T) {
+			palindrome, found := word.PermutationPalindrome(tc.inputString)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedPalindrome, palindrome)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package word
+
+import "strings"
+
+// Reverse returns the normalized word read backward, by grapheme clusters,
+// so that the combining marks stay on their base character.
+func Reverse(word string) string {
+	clusters := Normalize(word)
+	var builder strings.Builder
+	for i := len(clusters) - 1; i >= 0; i-- {
+		builder.WriteString(clusters[i])
+	}
+	return builder.String()
+}
+
+// IsReversePair reports whether the words read as each other backward once
+// normalized, like stressed and desserts. A palindrome is its own reverse,
+// the pair of a word with itself is not a reverse pair.
+func IsReversePair(a, b string) bool {
+	return !Equal(a, b) && Reverse(a) == strings.Join(Normalize(b), "")
+}
@@ -0,0 +1,83 @@
+package word_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverse(t *testing.T) {
+	type testCase struct {
+		name            string
+		inputString     string
+		expectedReverse string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:            "ascii word",
+			inputString:     "stressed",
+			expectedReverse: "desserts",
+		},
+		{
+			name:            "uppercase word",
+			inputString:     "Live",
+			expectedReverse: "evil",
+		},
+		{
+			name:            "combining mark kept on its base character",
+			inputString:     "café",
+			expectedReverse: "éfac",
+		},
+		{
+			name:            "empty word",
+			inputString:     "",
+			expectedReverse: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedReverse, word.Reverse(tc.inputString))
+		})
+	}
+}
+
+func TestIsReversePair(t *testing.T) {
+	type testCase struct {
+		name           string
+		a              string
+		b              string
+		expectedResult bool
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:           "reverse pair",
+			a:              "stressed",
+			b:              "desserts",
+			expectedResult: true,
+		},
+		{
+			name:           "reverse pair with different case",
+			a:              "Live",
+			b:              "EVIL",
+			expectedResult: true,
+		},
+		{
+			name:           "unrelated words",
+			a:              "live",
+			b:              "vile",
+			expectedResult: false,
+		},
+		{
+			name:           "palindrome with itself",
+			a:              "level",
+			b:              "Level",
+			expectedResult: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedResult, word.IsReversePair(tc.a, tc.b))
+		})
+	}
+}
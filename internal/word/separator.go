@@ -0,0 +1,21 @@
+package word
+
+import "strings"
+
+// StripSeparators removes every separator character from the word.
+func StripSeparators(word, separators string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(separators, r) {
+			return -1
+		}
+		return r
+	}, word)
+}
+
+// Segments splits the word at every separator character, dropping the
+// empty segments.
+func Segments(word, separators string) []string {
+	return strings.FieldsFunc(word, func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	})
+}
@@ -0,0 +1,62 @@
+package word_test
+
+import (
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripSeparators(t *testing.T) {
+	type testCase struct {
+		name           string
+		inputString    string
+		separators     string
+		expectedString string
+	}
+
+	for _, tc := range []testCase{
+		{name: "every separator", inputString: "a-b.a_b/a", separators: "-_./", expectedString: "ababa"},
+		{name: "only the given separators", inputString: "top_spot-x", separators: "_", expectedString: "topspot-x"},
+		{name: "no separators", inputString: "a-b", separators: "", expectedString: "a-b"},
+		{name: "only separators", inputString: "-_-", separators: "-_", expectedString: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedString, word.StripSeparators(tc.inputString, tc.separators))
+		})
+	}
+}
+
+func TestSegments(t *testing.T) {
+	type testCase struct {
+		name             string
+		inputString      string
+		separators       string
+		expectedSegments []string
+	}
+
+	for _, tc := range []testCase{
+		{
+			name:             "segments of a label key",
+			inputString:      "app.kubernetes.io/part-of",
+			separators:       "-_./",
+			expectedSegments: []string{"app", "kubernetes", "io", "part", "of"},
+		},
+		{
+			name:             "empty segments are dropped",
+			inputString:      "-level--kayak-",
+			separators:       "-",
+			expectedSegments: []string{"level", "kayak"},
+		},
+		{
+			name:             "word without separators",
+			inputString:      "level",
+			separators:       "-",
+			expectedSegments: []string{"level"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedSegments, word.Segments(tc.inputString, tc.separators))
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package word
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// regionalIndicatorSize is the UTF-8 length of every regional indicator.
+const regionalIndicatorSize = 4
+
+// utf8Source is a UTF-8 text read in place, one rune at a time.
+type utf8Source interface {
+	// length returns the length of the text in bytes.
+	length() int
+	// decodeRune returns the first rune of the text between start and limit.
+	decodeRune(start, limit int) (rune, int)
+	// decodeLastRune returns the last rune of the text between limit and end.
+	decodeLastRune(limit, end int) (rune, int)
+}
+
+type stringSource string
+
+func (s stringSource) length() int {
+	return len(s)
+}
+
+func (s stringSource) decodeRune(start, limit int) (rune, int) {
+	return utf8.DecodeRuneInString(string(s[start:limit]))
+}
+
+func (s stringSource) decodeLastRune(limit, end int) (rune, int) {
+	return utf8.DecodeLastRuneInString(string(s[limit:end]))
+}
+
+type bytesSource []byte
+
+func (s bytesSource) length() int {
+	return len(s)
+}
+
+func (s bytesSource) decodeRune(start, limit int) (rune, int) {
+	return utf8.DecodeRune(s[start:limit])
+}
+
+func (s bytesSource) decodeLastRune(limit, end int) (rune, int) {
+	return utf8.DecodeLastRune(s[limit:end])
+}
+
+// utf8Scanner compares the grapheme clusters at the ends of the text
+// moving two pointers toward the middle, without copying the text.
+type utf8Scanner[S utf8Source] struct {
+	text S
+
+	// The last run of regional indicators met walking backward, whose
+	// parity decides how the indicators are paired in flags.
+	regionalIndicatorsStart int
+	regionalIndicatorsEnd   int
+}
+
+// IsPalindromeString reports whether the text reads the same backward,
+// comparing its grapheme clusters in place with the simple case folding.
+// Unlike IsPalindrome, the text is not NFKC normalized and it is never
+// copied, so it can check large texts within a bounded memory.
+func IsPalindromeString(text string) bool {
+	scanner := utf8Scanner[stringSource]{text: stringSource(text)}
+	return scanner.isPalindrome()
+}
+
+// IsPalindromeBytes is IsPalindromeString for a UTF-8 byte slice.
+func IsPalindromeBytes(text []byte) bool {
+	scanner := utf8Scanner[bytesSource]{text: bytesSource(text)}
+	return scanner.isPalindrome()
+}
+
+func (s *utf8Scanner[S]) isPalindrome() bool {
+	front, back := 0, s.text.length()
+	for front < back {
+		frontEnd := s.nextClusterEnd(front, back)
+		backStart := s.previousClusterStart(front, back)
+		if frontEnd > backStart {
+			// the two pointers met in the middle cluster
+			return true
+		}
+		if !s.equalFold(front, frontEnd, backStart, back) {
+			return false
+		}
+		front, back = frontEnd, backStart
+	}
+	return true
+}
+
+// equalFold reports whether the two ranges of the text hold the same runes
+// under the simple case folding, like strings.EqualFold.
+func (s *utf8Scanner[S]) equalFold(aStart, aEnd, bStart, bEnd int) bool {
+	for aStart < aEnd && bStart < bEnd {
+		a, aSize := s.text.decodeRune(aStart, aEnd)
+		b, bSize := s.text.decodeRune(bStart, bEnd)
+		if !equalFoldRune(a, b) {
+			return false
+		}
+		aStart += aSize
+		bStart += bSize
+	}
+	return aStart == aEnd && bStart == bEnd
+}
+
+// equalFoldRune reports whether the runes are in the same simple case
+// folding orbit.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// nextClusterEnd returns the end of the grapheme cluster starting at start,
+// following the same boundaries of Graphemes.
+func (s *utf8Scanner[S]) nextClusterEnd(start, limit int) int {
+	previous, size := s.text.decodeRune(start, limit)
+	end := start + size
+	regionalIndicatorsInCluster := 0
+	if isRegionalIndicator(previous) {
+		regionalIndicatorsInCluster++
+	}
+
+	for end < limit {
+		current, currentSize := s.text.decodeRune(end, limit)
+		if !continuesCluster(previous, current, regionalIndicatorsInCluster) {
+			break
+		}
+		if isRegionalIndicator(current) {
+			regionalIndicatorsInCluster++
+		}
+		previous = current
+		end += currentSize
+	}
+	return end
+}
+
+// previousClusterStart returns the start of the grapheme cluster ending at
+// end, following the same boundaries of Graphemes.
+func (s *utf8Scanner[S]) previousClusterStart(limit, end int) int {
+	current, size := s.text.decodeLastRune(limit, end)
+	start := end - size
+
+	for start > limit {
+		previous, previousSize := s.text.decodeLastRune(limit, start)
+		var continues bool
+		if isRegionalIndicator(previous) && isRegionalIndicator(current) {
+			// flags pair the regional indicators from the start of their run
+			continues = s.regionalIndicatorsBefore(start)%regionalIndicatorsInFlag == 1
+		} else {
+			continues = continuesCluster(previous, current, 0)
+		}
+		if !continues {
+			break
+		}
+		current = previous
+		start -= previousSize
+	}
+	return start
+}
+
+// regionalIndicatorsBefore returns how many regional indicators precede the
+// one starting at position in its run. The run is found once and reused
+// while the back pointer walks it.
+func (s *utf8Scanner[S]) regionalIndicatorsBefore(position int) int {
+	if position < s.regionalIndicatorsStart || position > s.regionalIndicatorsEnd {
+		s.regionalIndicatorsStart = position
+		s.regionalIndicatorsEnd = position
+		for s.regionalIndicatorsStart > 0 {
+			r, size := s.text.decodeLastRune(0, s.regionalIndicatorsStart)
+			if !isRegionalIndicator(r) {
+				break
+			}
+			s.regionalIndicatorsStart -= size
+		}
+	}
+	return (position - s.regionalIndicatorsStart) / regionalIndicatorSize
+}
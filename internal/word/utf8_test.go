@@ -0,0 +1,69 @@
+package word_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdimonaco/e2e-framework-usage-demo-talk/internal/word"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPalindromeString(t *testing.T) {
+	type testCase struct {
+		name         string
+		inputString  string
+		isPalindrome bool
+	}
+
+	for _, tc := range []testCase{
+		{name: "empty text", inputString: "", isPalindrome: true},
+		{name: "single character", inputString: "a", isPalindrome: true},
+		{name: "even palindrome", inputString: "abba", isPalindrome: true},
+		{name: "odd palindrome", inputString: "racecar", isPalindrome: true},
+		{name: "not palindrome", inputString: "racecars", isPalindrome: false},
+		{name: "case insensitive", inputString: "RaceCar", isPalindrome: true},
+		{name: "greek sigma forms", inputString: "Σας", isPalindrome: true},
+		{name: "multibyte characters", inputString: "日本日", isPalindrome: true},
+		{name: "combining marks stay attached", inputString: "éaé", isPalindrome: true},
+		{name: "combining marks are not reversed", inputString: "é́e", isPalindrome: false},
+		{name: "emoji with modifier", inputString: "👍🏽x👍🏽", isPalindrome: true},
+		{name: "emoji zwj sequence", inputString: "👨‍👩‍👧a👨‍👩‍👧", isPalindrome: true},
+		{name: "same flags", inputString: "🇮🇹🇫🇷🇮🇹", isPalindrome: true},
+		{name: "reversed flag", inputString: "🇮🇹🇹🇮", isPalindrome: false},
+		{name: "odd run of regional indicators", inputString: "🇮🇹🇮🇹🇩", isPalindrome: false},
+		{name: "crlf is a single cluster", inputString: "a\r\na", isPalindrome: true},
+		{name: "invalid utf-8", inputString: "a\xffa", isPalindrome: true},
+		{name: "not NFKC normalized", inputString: "ﬁif", isPalindrome: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.isPalindrome, word.IsPalindromeString(tc.inputString))
+			assert.Equal(t, tc.isPalindrome, word.IsPalindromeBytes([]byte(tc.inputString)))
+		})
+	}
+}
+
+func TestIsPalindromeStringAgreesWithIsPalindrome(t *testing.T) {
+	for _, input := range []string{
+		"kayak", "Level", "abcba", "abcab", "é", "éaé",
+		"👍🏽👍", "🇫🇷a🇫🇷", "🇫🇷🇮🇹", "x🇮🇹🇮🇹🇮x",
+	} {
+		assert.Equal(t, word.IsPalindrome(input), word.IsPalindromeString(input), input)
+	}
+}
+
+func TestIsPalindromeStringMemoryDoesNotGrowWithTheText(t *testing.T) {
+	half := strings.Repeat("ab🇮🇹é👍🏽", 1<<14)
+	var reversed strings.Builder
+	clusters := word.Graphemes(half)
+	for i := len(clusters) - 1; i >= 0; i-- {
+		reversed.WriteString(clusters[i])
+	}
+	text := half + "x" + reversed.String()
+
+	assert.True(t, word.IsPalindromeString(text))
+	assert.Equal(
+		t,
+		testing.AllocsPerRun(1, func() { word.IsPalindromeString("aba") }),
+		testing.AllocsPerRun(1, func() { word.IsPalindromeString(text) }),
+	)
+}